@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"strings"
+)
+
+const (
+	// ADMinClasses is the minimum amount of character categories that are required by
+	// the Active Directory "passwords must meet complexity requirements" policy
+	ADMinClasses int64 = 3
+	// adMinTokenLength is the minimum length of an account name or display name token
+	// that is checked by the Active Directory complexity policy
+	adMinTokenLength = 3
+	// adTokenDelimiters is the list of characters Active Directory uses to split the
+	// display name into tokens
+	adTokenDelimiters = ",.-_ #\t—–"
+)
+
+// ADForbiddenTokens returns the list of tokens that are not allowed to be part of a
+// password according to the Active Directory complexity policy. The sAMAccountName is
+// only checked if it is at least 3 characters long. The display name is split into
+// tokens at commas, periods, dashes, underscores, spaces, pound signs and tabs and
+// each token that is at least 3 characters long is forbidden.
+func ADForbiddenTokens(samAccountName, displayName string) []string {
+	var tokens []string
+	if len([]rune(samAccountName)) >= adMinTokenLength {
+		tokens = append(tokens, samAccountName)
+	}
+	for _, token := range strings.FieldsFunc(displayName, func(char rune) bool {
+		return strings.ContainsRune(adTokenDelimiters, char)
+	}) {
+		if len([]rune(token)) >= adMinTokenLength {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// WithADComplexity configures the generator to generate passwords that meet the Active
+// Directory "passwords must meet complexity requirements" policy for the account with
+// the given sAMAccountName and display name. Instead of fixed per-mode minimums, the
+// generated passwords contain characters of at least 3 different character modes and
+// do not contain the account name or any token of the display name.
+func WithADComplexity(samAccountName, displayName string) Option {
+	return func(config *Config) {
+		config.MinClasses = max(config.MinClasses, ADMinClasses)
+		config.Mode = maskEnsureModeCount(config.Mode, config.MinClasses)
+		config.ForbiddenWords = append(config.ForbiddenWords, ADForbiddenTokens(samAccountName, displayName)...)
+	}
+}
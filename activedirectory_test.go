@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestADForbiddenTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    string
+		display string
+		want    []string
+	}{
+		{"Account name and display name", "jdoe", "John Doe", []string{"jdoe", "John", "Doe"}},
+		{"Short account name", "jd", "", nil},
+		{"Delimiters", "", "Erik Lind-Berg, Jr.#Admin_Test\tUser", []string{
+			"Erik", "Lind", "Berg", "Admin", "Test", "User",
+		}},
+		{"Short tokens", "", "Li Bo Wu", nil},
+		{"Empty", "", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := ADForbiddenTokens(tt.user, tt.display)
+			if len(tokens) != len(tt.want) {
+				t.Fatalf("ADForbiddenTokens() failed, expected: %v, got: %v", tt.want, tokens)
+			}
+			for i := range tokens {
+				if tokens[i] != tt.want[i] {
+					t.Errorf("ADForbiddenTokens() failed, expected: %s, got: %s", tt.want[i], tokens[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithADComplexity(t *testing.T) {
+	c := NewConfig(WithModeMask(ModeLowerCase), WithADComplexity("jdoe", "John Doe"))
+	if c.MinClasses != ADMinClasses {
+		t.Errorf("WithADComplexity() failed, expected min classes: %d, got: %d", ADMinClasses,
+			c.MinClasses)
+	}
+	if maskModeCount(c.Mode) != ADMinClasses {
+		t.Errorf("WithADComplexity() failed, expected mode count: %d, got: %d", ADMinClasses,
+			maskModeCount(c.Mode))
+	}
+	if len(c.ForbiddenWords) != 3 {
+		t.Errorf("WithADComplexity() failed, expected 3 forbidden words, got: %d", len(c.ForbiddenWords))
+	}
+}
+
+func TestGenerator_GenerateADComplexity(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithADComplexity("abc", "Xyz Mno"),
+		WithModeMask(ModeLowerCase|ModeUpperCase|ModeNumeric|ModeSpecial), WithMinLength(8),
+		WithMaxLength(8))
+	generator := New(config)
+	for range 100 {
+		password, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		lower := strings.ToLower(password)
+		for _, token := range []string{"abc", "xyz", "mno"} {
+			if strings.Contains(lower, token) {
+				t.Errorf("Generate() failed, password %q contains forbidden token %q", password, token)
+			}
+		}
+		classes := make(map[Mode]struct{})
+		for _, char := range password {
+			classes[generator.modeOfChar(char)] = struct{}{}
+		}
+		if int64(len(classes)) < ADMinClasses {
+			t.Errorf("Generate() failed, password %q has less than %d character modes", password,
+				ADMinClasses)
+		}
+	}
+}
+
+func TestGeneratePronounceable_ADComplexity(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoPronounceable), WithADComplexity("ber", ""),
+		WithModeMask(ModeLowerCase|ModeUpperCase|ModeNumeric|ModeSpecial), WithMinLength(10),
+		WithMaxLength(14))
+	generator := New(config)
+	for range 200 {
+		password, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if strings.Contains(strings.ToLower(password), "ber") {
+			t.Errorf("Generate() failed, password %q contains forbidden token %q", password, "ber")
+		}
+		classes := make(map[Mode]struct{})
+		for _, char := range password {
+			classes[generator.modeOfChar(char)] = struct{}{}
+		}
+		if int64(len(classes)) < ADMinClasses {
+			t.Errorf("Generate() failed, password %q has less than %d character modes", password,
+				ADMinClasses)
+		}
+	}
+}
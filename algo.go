@@ -4,6 +4,12 @@
 
 package apg
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
 // Algorithm is a type wrapper for an int type to represent different
 // password generation algorithm
 type Algorithm int
@@ -25,6 +31,9 @@ const (
 	AlgoUnsupported
 )
 
+// ErrUnknownAlgorithm is returned if an algorithm name is not known
+var ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
 // IntToAlgo takes an int value as input and returns the corresponding
 // Algorithm
 func IntToAlgo(a int) Algorithm {
@@ -41,3 +50,56 @@ func IntToAlgo(a int) Algorithm {
 		return AlgoUnsupported
 	}
 }
+
+// String satisfies the fmt.Stringer interface for the Algorithm type
+func (a Algorithm) String() string {
+	switch a {
+	case AlgoPronounceable:
+		return "pronounceable"
+	case AlgoRandom:
+		return "random"
+	case AlgoCoinFlip:
+		return "coinflip"
+	case AlgoBinary:
+		return "binary"
+	default:
+		return "unsupported"
+	}
+}
+
+// AlgorithmFromString returns the Algorithm for the given name or number. Valid
+// names are "pronounceable" (or "0"), "random" (or "1"), "coinflip" (or "2") and
+// "binary" (or "3")
+func AlgorithmFromString(algo string) (Algorithm, error) {
+	switch strings.ToLower(strings.TrimSpace(algo)) {
+	case "pronounceable", "0":
+		return AlgoPronounceable, nil
+	case "random", "1":
+		return AlgoRandom, nil
+	case "coinflip", "2":
+		return AlgoCoinFlip, nil
+	case "binary", "3":
+		return AlgoBinary, nil
+	default:
+		return AlgoUnsupported, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, algo)
+	}
+}
+
+// MarshalText satisfies the encoding.TextMarshaler interface for the Algorithm type
+func (a Algorithm) MarshalText() ([]byte, error) {
+	if IntToAlgo(int(a)) == AlgoUnsupported {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownAlgorithm, a)
+	}
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText satisfies the encoding.TextUnmarshaler interface for the Algorithm
+// type. See AlgorithmFromString for the valid values
+func (a *Algorithm) UnmarshalText(text []byte) error {
+	algo, err := AlgorithmFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*a = algo
+	return nil
+}
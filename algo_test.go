@@ -5,6 +5,7 @@
 package apg
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -30,6 +31,81 @@ func TestIntToAlgo(t *testing.T) {
 	}
 }
 
+func TestAlgorithm_String(t *testing.T) {
+	tt := []struct {
+		a Algorithm
+		e string
+	}{
+		{AlgoPronounceable, "pronounceable"},
+		{AlgoRandom, "random"},
+		{AlgoCoinFlip, "coinflip"},
+		{AlgoBinary, "binary"},
+		{AlgoUnsupported, "unsupported"},
+		{Algorithm(-1), "unsupported"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.e, func(t *testing.T) {
+			if got := tc.a.String(); got != tc.e {
+				t.Errorf("Algorithm.String() failed, expected: %s, got: %s", tc.e, got)
+			}
+		})
+	}
+}
+
+func TestAlgorithmFromString(t *testing.T) {
+	tt := []struct {
+		s string
+		e Algorithm
+	}{
+		{"pronounceable", AlgoPronounceable},
+		{"0", AlgoPronounceable},
+		{"Random", AlgoRandom},
+		{" 1 ", AlgoRandom},
+		{"coinflip", AlgoCoinFlip},
+		{"binary", AlgoBinary},
+		{"3", AlgoBinary},
+	}
+	for _, tc := range tt {
+		t.Run(tc.s, func(t *testing.T) {
+			a, err := AlgorithmFromString(tc.s)
+			if err != nil {
+				t.Fatalf("AlgorithmFromString() failed: %s", err)
+			}
+			if a != tc.e {
+				t.Errorf("AlgorithmFromString() failed, expected: %s, got: %s", tc.e, a)
+			}
+		})
+	}
+	if _, err := AlgorithmFromString("4"); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("AlgorithmFromString() expected ErrUnknownAlgorithm, got: %v", err)
+	}
+}
+
+func TestAlgorithm_MarshalText(t *testing.T) {
+	for _, algo := range []Algorithm{AlgoPronounceable, AlgoRandom, AlgoCoinFlip, AlgoBinary} {
+		t.Run(algo.String(), func(t *testing.T) {
+			text, err := algo.MarshalText()
+			if err != nil {
+				t.Fatalf("Algorithm.MarshalText() failed: %s", err)
+			}
+			var got Algorithm
+			if err = got.UnmarshalText(text); err != nil {
+				t.Fatalf("Algorithm.UnmarshalText() failed: %s", err)
+			}
+			if got != algo {
+				t.Errorf("Algorithm text round trip failed, expected: %s, got: %s", algo, got)
+			}
+		})
+	}
+	if _, err := AlgoUnsupported.MarshalText(); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("Algorithm.MarshalText() expected ErrUnknownAlgorithm, got: %v", err)
+	}
+	var algo Algorithm
+	if err := algo.UnmarshalText([]byte("dice")); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("Algorithm.UnmarshalText() expected ErrUnknownAlgorithm, got: %v", err)
+	}
+}
+
 func FuzzIntToAlgo(f *testing.F) {
 	f.Add(-1)  // Test negative input
 	f.Add(4)   // Test out-of-range positive input
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Charset represents a set of distinct characters (Unicode code points). The
+// characters keep the order in which they have been added to the set. A Charset
+// is immutable, all set operations return a new Charset. The zero value is an
+// empty Charset.
+type Charset struct {
+	chars []rune
+	index map[rune]struct{}
+}
+
+// NewCharset returns a new Charset that consists of the characters of the given
+// strings. The strings are normalized to NFC first, so that i. e. a decomposed
+// "é" results in a single character. Duplicate characters are only added once
+func NewCharset(chars ...string) Charset {
+	charset := Charset{index: make(map[rune]struct{})}
+	for _, list := range chars {
+		for _, char := range norm.NFC.String(list) {
+			charset.add(char)
+		}
+	}
+	return charset
+}
+
+// CharsetFromRange returns a new Charset that consists of all characters from
+// first to last (inclusive). Invalid code points and characters that change under
+// NFC normalization are skipped. If last is smaller than first, the Charset is empty
+func CharsetFromRange(first, last rune) Charset {
+	charset := Charset{index: make(map[rune]struct{})}
+	for char := int64(first); char <= int64(last); char++ {
+		if utf8.ValidRune(rune(char)) && isNFC(rune(char)) {
+			charset.add(rune(char))
+		}
+	}
+	return charset
+}
+
+// CharsetFromCategory returns a new Charset that consists of all printable characters
+// of the given Unicode category (i. e. "Lu" or "Sc") or script (i. e. "Greek") that
+// do not change under NFC normalization. If the category or script is unknown, an
+// error wrapping ErrInvalidCharRange is returned
+func CharsetFromCategory(name string) (Charset, error) {
+	table, ok := unicode.Categories[name]
+	if !ok {
+		table, ok = unicode.Scripts[name]
+	}
+	if !ok {
+		return Charset{}, fmt.Errorf("%w: unknown unicode category or script %q", ErrInvalidCharRange, name)
+	}
+	charset := Charset{index: make(map[rune]struct{})}
+	for _, r16 := range table.R16 {
+		for char := rune(r16.Lo); char <= rune(r16.Hi); char += rune(r16.Stride) {
+			if unicode.IsPrint(char) && isNFC(char) {
+				charset.add(char)
+			}
+		}
+	}
+	for _, r32 := range table.R32 {
+		for char := rune(r32.Lo); char <= rune(r32.Hi); char += rune(r32.Stride) {
+			if unicode.IsPrint(char) && isNFC(char) {
+				charset.add(char)
+			}
+		}
+	}
+	return charset, nil
+}
+
+// CharsetFromMode returns a new Charset that consists of the built-in characters of
+// all character modes set in the given Mode bitmask. If ModeHumanReadable or
+// ModeDictation is set, the human-readable or dictation-safe characters are used
+func CharsetFromMode(mask ModeMask) Charset {
+	charset := Charset{index: make(map[rune]struct{})}
+	for _, mode := range charModes {
+		if !MaskHasMode(mask, mode) {
+			continue
+		}
+		for _, char := range builtinCharRangeForMode(mode, mask) {
+			charset.add(char)
+		}
+	}
+	return charset
+}
+
+// Union returns a new Charset that consists of the characters of the Charset
+// followed by the characters of the other Charset
+func (c Charset) Union(other Charset) Charset {
+	charset := Charset{index: make(map[rune]struct{}, len(c.chars)+len(other.chars))}
+	for _, char := range c.chars {
+		charset.add(char)
+	}
+	for _, char := range other.chars {
+		charset.add(char)
+	}
+	return charset
+}
+
+// Difference returns a new Charset that consists of the characters of the Charset
+// that are not part of the other Charset
+func (c Charset) Difference(other Charset) Charset {
+	charset := Charset{index: make(map[rune]struct{}, len(c.chars))}
+	for _, char := range c.chars {
+		if !other.Contains(char) {
+			charset.add(char)
+		}
+	}
+	return charset
+}
+
+// Intersect returns a new Charset that consists of the characters of the Charset
+// that are also part of the other Charset
+func (c Charset) Intersect(other Charset) Charset {
+	charset := Charset{index: make(map[rune]struct{})}
+	for _, char := range c.chars {
+		if other.Contains(char) {
+			charset.add(char)
+		}
+	}
+	return charset
+}
+
+// filter returns a new Charset that consists of the characters of the Charset for
+// which the given function returns true
+func (c Charset) filter(keep func(char rune) bool) Charset {
+	charset := Charset{index: make(map[rune]struct{}, len(c.chars))}
+	for _, char := range c.chars {
+		if keep(char) {
+			charset.add(char)
+		}
+	}
+	return charset
+}
+
+// Contains returns true if the given character is part of the Charset
+func (c Charset) Contains(char rune) bool {
+	_, ok := c.index[char]
+	return ok
+}
+
+// Len returns the amount of characters in the Charset
+func (c Charset) Len() int {
+	return len(c.chars)
+}
+
+// Runes returns a copy of the characters of the Charset
+func (c Charset) Runes() []rune {
+	chars := make([]rune, len(c.chars))
+	copy(chars, c.chars)
+	return chars
+}
+
+// String satisfies the fmt.Stringer interface for the Charset type. It returns
+// the characters of the Charset as string
+func (c Charset) String() string {
+	var builder strings.Builder
+	for _, char := range c.chars {
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+// isNFC returns true if the given character does not change under NFC normalization
+func isNFC(char rune) bool {
+	return norm.NFC.IsNormalString(string(char))
+}
+
+// add adds the given character to the Charset, unless it is already part of it
+func (c *Charset) add(char rune) {
+	if _, ok := c.index[char]; ok {
+		return
+	}
+	c.index[char] = struct{}{}
+	c.chars = append(c.chars, char)
+}
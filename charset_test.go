@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewCharset(t *testing.T) {
+	tests := []struct {
+		name  string
+		chars []string
+		want  string
+	}{
+		{"Empty", nil, ""},
+		{"Single string", []string{"abc"}, "abc"},
+		{"Duplicates", []string{"abcabc"}, "abc"},
+		{"Multiple strings", []string{"abc", "cde"}, "abcde"},
+		{"Unicode", []string{"äöüä"}, "äöü"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charset := NewCharset(tt.chars...)
+			if charset.String() != tt.want {
+				t.Errorf("NewCharset() failed, expected: %s, got: %s", tt.want, charset.String())
+			}
+			if charset.Len() != len([]rune(tt.want)) {
+				t.Errorf("NewCharset() failed, expected length: %d, got: %d", len([]rune(tt.want)),
+					charset.Len())
+			}
+		})
+	}
+}
+
+func TestCharsetFromRange(t *testing.T) {
+	if charset := CharsetFromRange('a', 'z'); charset.String() != CharRangeAlphaLower {
+		t.Errorf("CharsetFromRange() failed, expected: %s, got: %s", CharRangeAlphaLower, charset.String())
+	}
+	if charset := CharsetFromRange('z', 'a'); charset.Len() != 0 {
+		t.Errorf("CharsetFromRange() failed, expected empty charset, got: %s", charset.String())
+	}
+}
+
+func TestCharsetFromCategory(t *testing.T) {
+	charset, err := CharsetFromCategory("Nd")
+	if err != nil {
+		t.Fatalf("CharsetFromCategory() failed: %s", err)
+	}
+	for _, char := range CharRangeNumeric + "٣" {
+		if !charset.Contains(char) {
+			t.Errorf("CharsetFromCategory() failed, expected charset to contain %q", char)
+		}
+	}
+	greek, err := CharsetFromCategory("Greek")
+	if err != nil {
+		t.Fatalf("CharsetFromCategory() failed: %s", err)
+	}
+	if !greek.Contains('λ') || greek.Contains('a') {
+		t.Errorf("CharsetFromCategory() failed, unexpected characters in Greek script")
+	}
+	if _, err = CharsetFromCategory("Unknown"); !errors.Is(err, ErrInvalidCharRange) {
+		t.Errorf("CharsetFromCategory() failed, expected error: %s, got: %s", ErrInvalidCharRange, err)
+	}
+}
+
+func TestCharsetFromMode(t *testing.T) {
+	tests := []struct {
+		name string
+		mask ModeMask
+		want string
+	}{
+		{"Lower case", ModeLowerCase, CharRangeAlphaLower},
+		{"Numeric human-readable", ModeNumeric | ModeHumanReadable, CharRangeNumericHuman},
+		{"Special", ModeSpecial, CharRangeSpecial},
+		{"Lower and upper case", ModeLowerCase | ModeUpperCase, CharRangeAlphaLower + CharRangeAlphaUpper},
+		{"None", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if charset := CharsetFromMode(tt.mask); charset.String() != tt.want {
+				t.Errorf("CharsetFromMode() failed, expected: %s, got: %s", tt.want, charset.String())
+			}
+		})
+	}
+}
+
+func TestCharset_SetOperations(t *testing.T) {
+	a := NewCharset("abcd")
+	b := NewCharset("cdef")
+	tests := []struct {
+		name    string
+		charset Charset
+		want    string
+	}{
+		{"Union", a.Union(b), "abcdef"},
+		{"Difference", a.Difference(b), "ab"},
+		{"Intersect", a.Intersect(b), "cd"},
+		{"Union with zero value", Charset{}.Union(a), "abcd"},
+		{"Difference with zero value", a.Difference(Charset{}), "abcd"},
+		{"Intersect with zero value", a.Intersect(Charset{}), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.charset.String() != tt.want {
+				t.Errorf("%s failed, expected: %s, got: %s", tt.name, tt.want, tt.charset.String())
+			}
+		})
+	}
+	if a.String() != "abcd" || b.String() != "cdef" {
+		t.Errorf("set operations are not supposed to modify the original charsets")
+	}
+}
+
+func TestCharset_Contains(t *testing.T) {
+	charset := NewCharset("abc")
+	if !charset.Contains('a') {
+		t.Errorf("Contains() failed, expected charset to contain %q", 'a')
+	}
+	if charset.Contains('d') {
+		t.Errorf("Contains() failed, expected charset to not contain %q", 'd')
+	}
+	if (Charset{}).Contains('a') {
+		t.Errorf("Contains() failed, expected zero value charset to not contain %q", 'a')
+	}
+}
+
+func TestCharset_Runes(t *testing.T) {
+	charset := NewCharset("abc")
+	runes := charset.Runes()
+	runes[0] = 'x'
+	if charset.String() != "abc" {
+		t.Errorf("Runes() failed, modifying the returned slice is not supposed to modify the charset")
+	}
+}
@@ -7,10 +7,14 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/wneessen/apg-go"
 )
@@ -22,25 +26,71 @@ const MinimumAmountTooHigh = "WARNING: You have selected a minimum amount of cha
 	"to extraordinary calculation times resulting in apg-go never finishing\n" +
 	"the job. Please consider lowering the value.\n\n"
 
+// PreviousPasswordEnv is the name of the environment variable that holds the previous
+// password in rotation mode. If it is not set, the previous password is read from stdin
+const PreviousPasswordEnv = "APG_PREVIOUS_PASSWORD"
+
 func main() {
-	config := apg.NewConfig()
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "koremutake":
+			os.Exit(koremutake(os.Args[2:]))
+		case "profiles":
+			os.Exit(profiles(os.Args[2:]))
+		case "unspell":
+			os.Exit(unspell(os.Args[2:]))
+		}
+	}
+
+	// The defaults of the config file and the environment are overridden by the
+	// CLI flags
+	config := apg.NewConfig(apg.WithAlgorithm(apg.AlgoRandom))
+	if err := configDefaults(config); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to load configuration: %s\n", err)
+		os.Exit(1)
+	}
 
 	// Configure and parse the CLI flags
 	// See usage() for flag details
 	var algorithm int
-	var modeString string
-	var complexPass, humanReadable, lowerCase, numeric, special, showVer, upperCase bool
-	flag.IntVar(&algorithm, "a", 1, "")
-	flag.BoolVar(&config.BinaryHexMode, "bh", false, "")
-	flag.BoolVar(&config.BinaryNewline, "bn", false, "")
+	var charClasses charClassFlag
+	var charRangeLower, charRangeNumeric, charRangeSpecial, charRangeUpper string
+	var rotationDistance, rotationSubstring int64
+	var adDisplayName, adUserName, capitalization, classWeights, confusables, escapeContext, firstCharModes,
+		keyboardLayouts, lastCharModes, lengthStrategy, modeString, outputFormat, passwordRules, profileName,
+		pwQualityFile, safeFor, spellingAlphabet, spellingRenderer, syllableSet string
+	var adComplexity, complexPass, dictation, humanReadable, lowerCase, numeric, pwQuality, rotation, special,
+		showEntropy, showVer, upperCase bool
+	flag.IntVar(&algorithm, "a", int(config.Algorithm), "")
+	flag.BoolVar(&config.BinaryHexMode, "bh", config.BinaryHexMode, "")
+	flag.BoolVar(&config.BinaryNewline, "bn", config.BinaryNewline, "")
 	flag.BoolVar(&complexPass, "C", false, "")
-	flag.StringVar(&config.ExcludeChars, "E", "", "")
-	flag.Int64Var(&config.FixedLength, "f", 0, "")
-	flag.BoolVar(&config.MobileGrouping, "g", false, "")
+	flag.StringVar(&confusables, "cf", "", "")
+	flag.StringVar(&charRangeLower, "cL", "", "")
+	flag.StringVar(&charRangeNumeric, "cN", "", "")
+	flag.StringVar(&charRangeSpecial, "cS", "", "")
+	flag.StringVar(&charRangeUpper, "cU", "", "")
+	flag.Var(&charClasses, "cX", "")
+	flag.StringVar(&classWeights, "cw", "", "")
+	flag.BoolVar(&adComplexity, "D", false, "")
+	flag.BoolVar(&dictation, "d", false, "")
+	flag.Int64Var(&config.CheckCharGroupSize, "dc", config.CheckCharGroupSize, "")
+	flag.StringVar(&adDisplayName, "Dn", "", "")
+	flag.StringVar(&adUserName, "Du", "", "")
+	flag.BoolVar(&showEntropy, "e", false, "")
+	flag.StringVar(&config.ExcludeChars, "E", config.ExcludeChars, "")
+	flag.Int64Var(&config.FixedLength, "f", config.FixedLength, "")
+	flag.StringVar(&firstCharModes, "fc", "", "")
+	flag.BoolVar(&config.MobileGrouping, "g", config.MobileGrouping, "")
 	flag.BoolVar(&humanReadable, "H", false, "")
-	flag.BoolVar(&config.SpellPassword, "l", false, "")
+	flag.StringVar(&keyboardLayouts, "k", "", "")
+	flag.BoolVar(&config.SpellPassword, "l", config.SpellPassword, "")
 	flag.BoolVar(&lowerCase, "L", false, "")
+	flag.StringVar(&lastCharModes, "lc", "", "")
+	flag.StringVar(&lengthStrategy, "ls", "", "")
 	flag.Int64Var(&config.MinLength, "m", config.MinLength, "")
+	flag.Int64Var(&config.MinClasses, "mC", config.MinClasses, "")
 	flag.Int64Var(&config.MinLowerCase, "mL", config.MinLowerCase, "")
 	flag.Int64Var(&config.MinNumeric, "mN", config.MinNumeric, "")
 	flag.Int64Var(&config.MinSpecial, "mS", config.MinSpecial, "")
@@ -48,15 +98,58 @@ func main() {
 	flag.Int64Var(&config.NumberPass, "n", config.NumberPass, "")
 	flag.StringVar(&modeString, "M", "", "")
 	flag.BoolVar(&numeric, "N", false, "")
-	flag.BoolVar(&config.CheckHIBP, "p", false, "")
+	flag.StringVar(&outputFormat, "o", "", "")
+	flag.StringVar(&escapeContext, "oe", "", "")
+	flag.BoolVar(&config.CheckHIBP, "p", config.CheckHIBP, "")
+	flag.StringVar(&profileName, "P", "", "")
+	flag.StringVar(&capitalization, "pc", "", "")
+	flag.BoolVar(&config.Leetspeak, "pl", config.Leetspeak, "")
+	flag.StringVar(&config.SyllableSeparator, "ps", config.SyllableSeparator, "")
+	flag.BoolVar(&pwQuality, "Q", false, "")
+	flag.StringVar(&pwQualityFile, "Qf", "", "")
+	flag.StringVar(&passwordRules, "R", "", "")
+	flag.BoolVar(&rotation, "r", false, "")
+	flag.Int64Var(&rotationDistance, "rd", apg.DefaultRotationMinDistance, "")
+	flag.Int64Var(&rotationSubstring, "rs", apg.DefaultRotationMaxSharedSubstring, "")
 	flag.BoolVar(&special, "S", false, "")
-	flag.BoolVar(&config.SpellPronounceable, "t", false, "")
+	flag.StringVar(&safeFor, "sf", "", "")
+	flag.StringVar(&spellingAlphabet, "sa", "", "")
+	flag.StringVar(&spellingRenderer, "sr", "", "")
+	flag.StringVar(&syllableSet, "sy", "", "")
+	flag.BoolVar(&config.SpellPronounceable, "t", config.SpellPronounceable, "")
+	flag.BoolVar(&config.UniqueChars, "u", config.UniqueChars, "")
 	flag.BoolVar(&upperCase, "U", false, "")
 	flag.BoolVar(&showVer, "v", false, "")
 	flag.Int64Var(&config.MaxLength, "x", config.MaxLength, "")
+	flag.Int64Var(&config.MaxClassRepeat, "xC", config.MaxClassRepeat, "")
+	flag.Int64Var(&config.MaxLowerCase, "xL", config.MaxLowerCase, "")
+	flag.Int64Var(&config.MaxNumeric, "xN", config.MaxNumeric, "")
+	flag.Int64Var(&config.MaxSequence, "xQ", config.MaxSequence, "")
+	flag.Int64Var(&config.MaxRepeat, "xR", config.MaxRepeat, "")
+	flag.Int64Var(&config.MaxSpecial, "xS", config.MaxSpecial, "")
+	flag.Int64Var(&config.MaxUpperCase, "xU", config.MaxUpperCase, "")
 	flag.Usage = usage
 	flag.Parse()
 
+	// A named profile overrides the defaults, but not the explicitly given flags, so
+	// the flags are parsed again on top of the profile
+	if profileName != "" {
+		profile, err := profileByName(profileName)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to select profile: %s\n", err)
+			os.Exit(1)
+		}
+		profileConf, err := profileConfig(profile)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to configure profile: %s\n", err)
+			os.Exit(1)
+		}
+		*config = *profileConf
+		algorithm = int(config.Algorithm)
+		charClasses = nil
+		_ = flag.CommandLine.Parse(os.Args[1:])
+	}
+
 	// Show version and exit
 	if showVer {
 		_, _ = os.Stderr.WriteString(`apg-go // A "Automated Password Generator"-clone ` +
@@ -76,10 +169,141 @@ func main() {
 		config.Mode = apg.ModesFromFlags(modeString)
 	}
 
+	// Custom character ranges and character classes
+	configCharRanges(config, map[apg.Mode]string{
+		apg.ModeLowerCase: charRangeLower,
+		apg.ModeNumeric:   charRangeNumeric,
+		apg.ModeSpecial:   charRangeSpecial,
+		apg.ModeUpperCase: charRangeUpper,
+	})
+	config.CharClasses = append(config.CharClasses, charClasses...)
+
+	// Character class weights
+	if classWeights != "" {
+		weights, err := apg.ClassWeightsFromString(classWeights)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse character class weights: %s\n", err)
+			os.Exit(1)
+		}
+		for mode, weight := range weights {
+			apg.WithClassWeight(mode, weight)(config)
+		}
+	}
+
+	// Confusable character groups
+	if confusables != "" {
+		mask, err := apg.ConfusablesFromString(confusables)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse confusable groups: %s\n", err)
+			os.Exit(1)
+		}
+		apg.WithConfusables(mask)(config)
+	}
+
+	// Length strategy for pronounceable passwords
+	if lengthStrategy != "" {
+		strategy, err := apg.LengthStrategyFromString(lengthStrategy)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse length strategy: %s\n", err)
+			os.Exit(1)
+		}
+		config.LengthStrategy = strategy
+	}
+
+	// Syllable set for pronounceable passwords
+	if syllableSet != "" {
+		set, err := apg.SyllableSetByName(syllableSet)
+		if errors.Is(err, apg.ErrUnknownSyllableSet) {
+			set, err = apg.LoadSyllableSet(syllableSet)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to load syllable set: %s\n", err)
+			os.Exit(1)
+		}
+		config.SyllableSet = set
+	}
+
+	// Spelling alphabet for spelled passwords
+	if spellingAlphabet != "" {
+		alphabet, err := spellingAlphabetFromFlag(spellingAlphabet)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to select spelling alphabet: %s\n", err)
+			os.Exit(1)
+		}
+		config.SpellingAlphabet = alphabet
+	}
+
+	// Output format of spelled passwords
+	if spellingRenderer != "" {
+		renderer, err := apg.SpellingRendererFromString(spellingRenderer)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse spelling renderer: %s\n", err)
+			os.Exit(1)
+		}
+		config.SpellingRenderer = renderer
+	}
+
+	// Escaping of the generated passwords for the target context
+	escape := apg.EscapeNone
+	if escapeContext != "" {
+		context, err := apg.EscapeContextFromString(escapeContext)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse escape context: %s\n", err)
+			os.Exit(1)
+		}
+		escape = context
+	}
+
+	// Only use characters that are safe for the target context
+	if safeFor != "" {
+		context, err := apg.EscapeContextFromString(safeFor)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse safe-for context: %s\n", err)
+			os.Exit(1)
+		}
+		config.SafeFor = context
+	}
+
+	// Capitalization style for pronounceable passwords
+	if capitalization != "" {
+		style, err := apg.CapitalizationStyleFromString(capitalization)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse capitalization style: %s\n", err)
+			os.Exit(1)
+		}
+		config.Capitalization = style
+	}
+
+	// Keyboard layouts
+	if keyboardLayouts != "" {
+		layouts, err := apg.KeyboardLayoutsFromString(keyboardLayouts)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse keyboard layouts: %s\n", err)
+			os.Exit(1)
+		}
+		apg.WithKeyboardLayouts(layouts...)(config)
+	}
+
+	// Dictation-safe passwords are always spelled
+	if dictation {
+		config.Mode = apg.MaskSetMode(config.Mode, apg.ModeDictation)
+	}
+	if apg.MaskHasMode(config.Mode, apg.ModeDictation) {
+		config.SpellPassword = true
+	}
+
 	// For the "minimum amount of" modes we need to imply at the type
 	// of character mode is set
 	configMinRequirement(config)
 
+	// Character mode restrictions for the first and last character
+	if firstCharModes != "" {
+		config.FirstCharMode = apg.ModesFromFlags(firstCharModes)
+	}
+	if lastCharModes != "" {
+		config.LastCharMode = apg.ModesFromFlags(lastCharModes)
+	}
+
 	// Check if algorithm is supported
 	config.Algorithm = apg.IntToAlgo(algorithm)
 	if config.Algorithm == apg.AlgoUnsupported {
@@ -87,8 +311,69 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Generate the password based on the given flags and print it to stdout
-	generate(config)
+	// Import the password quality settings of libpwquality
+	if pwQuality || pwQualityFile != "" {
+		path := apg.DefaultPWQualityFile
+		if pwQualityFile != "" {
+			path = pwQualityFile
+		}
+		pwq, err := apg.LoadPWQualityFile(path)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to load pwquality settings: %s\n", err)
+			os.Exit(1)
+		}
+		apg.WithPWQuality(pwq)(config)
+	}
+
+	// Import the password rules in "passwordrules" syntax
+	if passwordRules != "" {
+		rules, err := apg.ParsePasswordRules(passwordRules)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse password rules: %s\n", err)
+			os.Exit(1)
+		}
+		apg.WithPasswordRules(rules)(config)
+	}
+
+	// Active Directory complexity requirements
+	if adComplexity || adUserName != "" || adDisplayName != "" {
+		apg.WithADComplexity(adUserName, adDisplayName)(config)
+	}
+
+	// Rotation mode: the new password has to differ from the previous one
+	if rotation {
+		previous, err := previousPassword()
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to read previous password: %s\n", err)
+			os.Exit(1)
+		}
+		apg.WithRotation(&apg.Rotation{
+			MaxSharedSubstring: rotationSubstring,
+			MinDistance:        rotationDistance,
+			Previous:           previous,
+		})(config)
+	}
+
+	// Show the estimated entropy of the generated passwords
+	if showEntropy {
+		entropy, err := apg.New(config).Entropy()
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to calculate entropy: %s\n", err)
+			os.Exit(1)
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Estimated entropy per password: %.2f bits\n", entropy)
+	}
+
+	// Generate the password based on the given flags and print it to stdout, either
+	// as plain text or in one of the structured output formats
+	if outputFormat != "" && !strings.EqualFold(outputFormat, "text") {
+		if err := generateStructured(config, outputFormat, escape); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	generate(config, escape)
 }
 
 // configMinRequirement configures the "minimum amount" feature
@@ -119,6 +404,19 @@ func configMinRequirement(config *apg.Config) {
 	}
 }
 
+// configCharRanges replaces the built-in character ranges of the character modes
+// with the given non-empty custom character ranges. A custom character range implies
+// the corresponding character mode
+func configCharRanges(config *apg.Config, charRanges map[apg.Mode]string) {
+	for mode, charRange := range charRanges {
+		if charRange == "" {
+			continue
+		}
+		apg.WithCharRange(mode, charRange)(config)
+		config.Mode = apg.MaskSetMode(config.Mode, mode)
+	}
+}
+
 // configOldStyle configures the old style character modes
 func configOldStyle(config *apg.Config, humanReadable, lowerCase, upperCase,
 	numeric, special, complexPass bool,
@@ -145,8 +443,13 @@ func configOldStyle(config *apg.Config, humanReadable, lowerCase, upperCase,
 	}
 }
 
-func generate(config *apg.Config) {
+func generate(config *apg.Config, escape apg.EscapeContext) {
 	generator := apg.New(config)
+	if escape != apg.EscapeNone && config.Algorithm == apg.AlgoBinary && !config.BinaryHexMode {
+		_, _ = fmt.Fprintln(os.Stderr, "failed to escape password: escaping binary secrets "+
+			"requires the hex representation (-bh)")
+		os.Exit(1)
+	}
 
 	// In binary mode we only generate a single secret
 	if config.Algorithm == apg.AlgoBinary {
@@ -155,6 +458,16 @@ func generate(config *apg.Config) {
 			_, _ = fmt.Fprintf(os.Stderr, "failed to generate password: %s\n", err)
 			os.Exit(1)
 		}
+		if config.SpellPassword {
+			if !config.BinaryHexMode {
+				_, _ = fmt.Fprintln(os.Stderr, "failed to spell password: spelling binary secrets "+
+					"requires the hex representation (-bh)")
+				os.Exit(1)
+			}
+			printSpelled(config, password, escape)
+			return
+		}
+		password = escapePassword(password, escape)
 		if config.BinaryNewline {
 			fmt.Println(password)
 			return
@@ -170,23 +483,18 @@ func generate(config *apg.Config) {
 			_, _ = fmt.Fprintf(os.Stderr, "failed to generate password: %s\n", err)
 			os.Exit(1)
 		}
-		if config.Algorithm == apg.AlgoRandom && config.SpellPassword {
-			spellPass, err := apg.Spell(password)
-			if err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "failed to spell password: %s\n", err)
-			}
-			fmt.Printf("%s (%s)\n", password, spellPass)
-			continue
-		}
-		if config.Algorithm == apg.AlgoPronounceable && config.SpellPronounceable {
+		switch {
+		case config.Algorithm == apg.AlgoPronounceable && config.SpellPronounceable:
 			pronouncePass, err := generator.Pronounce()
 			if err != nil {
 				_, _ = fmt.Fprintf(os.Stderr, "failed to pronounce password: %s\n", err)
 			}
-			fmt.Printf("%s (%s)\n", password, pronouncePass)
-			continue
+			fmt.Printf("%s (%s)\n", escapePassword(password, escape), pronouncePass)
+		case config.SpellPassword:
+			printSpelled(config, password, escape)
+		default:
+			fmt.Println(escapePassword(password, escape))
 		}
-		fmt.Println(password)
 
 		if config.CheckHIBP {
 			pwned, err := apg.HasBeenPwned(password)
@@ -201,6 +509,97 @@ func generate(config *apg.Config) {
 	}
 }
 
+// escapePassword returns the given password escaped for the given context. If the
+// password can not be represented in the context, the program exits with an error
+func escapePassword(password string, escape apg.EscapeContext) string {
+	escaped, err := apg.Escape(password, escape)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to escape password: %s\n", err)
+		os.Exit(1)
+	}
+	return escaped
+}
+
+// printSpelled prints the given password, escaped for the given context, together
+// with its spelled version in the configured spelling alphabet and output format
+func printSpelled(config *apg.Config, password string, escape apg.EscapeContext) {
+	spellPass, err := config.SpellingAlphabet.Render(password, config.SpellingRenderer)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to spell password: %s\n", err)
+		os.Exit(1)
+	}
+	password = escapePassword(password, escape)
+	if config.SpellingRenderer == apg.SpellingRendererSSML ||
+		config.SpellingRenderer == apg.SpellingRendererJSON {
+		fmt.Printf("%s\n%s\n", password, spellPass)
+		return
+	}
+	fmt.Printf("%s (%s)\n", password, spellPass)
+}
+
+// charClassFlag implements the flag.Value interface for custom character classes
+// in the NAME:MIN:CHARS format. The flag can be given multiple times
+type charClassFlag []apg.CharClass
+
+// String satisfies the flag.Value interface for charClassFlag
+func (c *charClassFlag) String() string {
+	classes := make([]string, 0, len(*c))
+	for _, class := range *c {
+		classes = append(classes, fmt.Sprintf("%s:%d:%s", class.Name, class.MinAmount, class.Chars))
+	}
+	return strings.Join(classes, ",")
+}
+
+// Set satisfies the flag.Value interface for charClassFlag
+func (c *charClassFlag) Set(value string) error {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return fmt.Errorf("character class needs to be in the NAME:MIN:CHARS format")
+	}
+	var minAmount int64
+	if parts[1] != "" {
+		amount, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || amount < 0 {
+			return fmt.Errorf("invalid minimum amount %q for character class %q", parts[1], parts[0])
+		}
+		minAmount = amount
+	}
+	*c = append(*c, apg.CharClass{Chars: parts[2], MinAmount: minAmount, Name: parts[0]})
+	return nil
+}
+
+// previousPassword returns the previous password for the rotation mode. The password
+// is read from the PreviousPasswordEnv environment variable or, if it is not set,
+// from the first line of stdin. It is never accepted as a command line argument, so
+// it does not show up in the shell history or the process list
+func previousPassword() (string, error) {
+	if previous, ok := os.LookupEnv(PreviousPasswordEnv); ok {
+		return previous, nil
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("no previous password given on stdin")
+	}
+	return strings.TrimRight(scanner.Text(), "\r"), nil
+}
+
+// spellingAlphabetFromFlag returns the spelling alphabet for the given flag value.
+// The value "locale" selects the alphabet based on the locale environment variables
+func spellingAlphabetFromFlag(name string) (apg.SpellingAlphabet, error) {
+	if !strings.EqualFold(name, "locale") {
+		return apg.SpellingAlphabetByName(name)
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(env); locale != "" {
+			return apg.SpellingAlphabetForLocale(locale), nil
+		}
+	}
+	return apg.SpellingAlphabetNATO, nil
+}
+
 // usage is used by the flag package to display the CLI usage message
 func usage() {
 	// Usage text
@@ -209,10 +608,41 @@ func usage() {
 		`A OSS "Automated Password Generator"-clone -- https://github.com/wneessen/apg-go/
 Created 2021-2024 by Winni Neessen (MIT licensed)
 
-apg [-a <algo>] [-m <length>] [-x <length>] [-L] [-U] [-N] [-S] [-H] [-C]
-    [-l] [-M mode] [-E char_string] [-n num_of_pass] [-mX number] [-t] [-p] [-v] [-h]
+apg [-P profile] [-a <algo>] [-m <length>] [-x <length>] [-L] [-U] [-N] [-S] [-H] [-C]
+    [-l] [-sa alphabet] [-sr renderer] [-M mode] [-E char_string] [-n num_of_pass]
+    [-o format] [-oe context] [-sf context] [-mX number] [-xX number]
+    [-cL chars] [-cN chars] [-cS chars] [-cU chars] [-cX name:min:chars] [-cf groups]
+    [-cw weights] [-k layouts] [-d] [-dc number] [-ls strategy]
+    [-ps separator] [-pc style] [-pl] [-sy set]
+    [-fc mode] [-lc mode] [-u] [-Q] [-Qf file] [-R rules] [-D] [-Du user]
+    [-Dn display_name] [-r] [-rd number] [-rs number] [-e] [-t] [-p] [-v] [-h]
+apg koremutake [number|word]...
+apg profiles list
+apg unspell [-sa alphabet] [word]...
+
+Subcommands:
+    koremutake           Convert numbers into Koremutake words and Koremutake words back into
+                         numbers, using the canonical 128 syllables table. If no values are
+                         given, they are read line by line from stdin
+    profiles list        List the user-defined and built-in profiles (see -P) with the estimated
+                         entropy of their passwords
+    unspell              Convert words of a spelling alphabet (see -sa) back into the spelled
+                         password. Words are separated by "/" or whitespace and common
+                         misspellings are corrected. If no words are given, spelled passwords
+                         are read line by line from stdin
+
+Configuration:
+    Defaults are read from $XDG_CONFIG_HOME/apg-go/config.toml (or config.json), or from the file
+    in $APG_CONFIG, and from APG_* environment variables named after the keys of the file (i. e.
+    APG_MIN_LENGTH=16). The environment overrides the file and the flags override both.
+    User-defined profiles are read from $XDG_CONFIG_HOME/apg-go/profiles.toml (or profiles.json),
+    or from the file in $APG_PROFILES
 
 Flags:
+    -P PROFILE           Use the settings of the named password policy profile. The profile overrides
+                         the configuration defaults; any other given flag overrides the profile
+                          - Built-in: pin6, wifi-wpa2, db-safe, ad-complex, legacy8, urlsafe,
+                            nist-800-63b (see "apg profiles list")
     -a ALGORITH          Choose the password generation algorithm (Default: 1)
                           - 0: pronounceable password generation (koremutake syllables)
                           - 1: random password generation according to password modes/flags
@@ -225,33 +655,128 @@ Flags:
     -x LENGTH            Maximum length of the password to be generated (Default: 20)
     -f LENGTH            Fixed length of the password to be generated (Ignores -m and -x)
                           - Note: Due to the way the pronounceable password algorithm works,
-	                        this setting might not always apply
+	                        this setting might not always apply (see -ls)
+    -ls STRATEGY         Length strategy for pronounceable passwords (Algo: 0) (Default: minimum)
+                          - minimum: add syllables until the length is reached (might exceed
+                            the length by up to one syllable)
+                          - exact: passwords with exactly the requested amount of characters
+                          - syllables: the length is the amount of syllables
+    -ps SEPARATOR        Separator between the syllables of pronounceable passwords (i. e. "-")
+                          - Note: the separator counts towards the password length
+    -pc STYLE            Capitalization style for pronounceable passwords (Default: random-letter)
+                          - random-letter: a coinflip for each syllable converts a random letter
+                          - none: no upper-case letters
+                          - first-syllable: the first letter of the first syllable
+                          - random-syllable: the first letter of a random syllable
+    -sy SET              Syllable set for pronounceable passwords (Default: koremutake)
+                          - Sets: koremutake, de, es, it, ja (romaji)
+                          - Any other value is read as a file with whitespace or comma
+                            separated lower-case syllables
+    -pl                  Randomly substitute letters of pronounceable passwords with leetspeak
+                         numbers (i. e. "e" with "3"). Requires numbers to be enabled
     -g                   When set, mobile-friendly character grouping will be enabled in Algo: 1
                           - Note: Grouping characters in random passwords makes them much
                             more predictable and lowers the entropy of the generated password.
     -n NUMBER            Amount of password to be generated (Default: 6)
                           - Note: Does not apply to binary mode (Algo: 3)
     -E CHARS             List of characters to be excluded in the generated password
-    -M [LUNSHClunshc]    New style password flags
+    -M [LUNSHCDlunshcd]  New style password flags
                           - Note: new-style flags have higher priority than any of the old-style flags
+    -cL CHARS            Custom set of lower-case characters (implies -L)
+    -cN CHARS            Custom set of numeric characters (implies -N)
+    -cS CHARS            Custom set of special characters (implies -S)
+    -cU CHARS            Custom set of upper-case characters (implies -U)
+                          - Note: custom sets replace the built-in sets, even if -H is set
+    -cX NAME:MIN:CHARS   Additional user-named character class and the minimum amount of its
+                         characters in the password (i. e. "brackets:1:()[]{}"). Can be given multiple times
+    -cw WEIGHTS          Comma-separated list of character class weights in percent (i. e. "L:35,U:35,N:20,S:10")
+                          - Classes: L, U, N, S and X (custom -cX characters)
+                          - Note: classes without a weight share the remaining percentage
+    -cf GROUPS           Comma-separated list of confusable character groups to avoid
+                          - Groups: 0Oo, 1lI|, 5S, 2Z, 8B, rn/m, vv/w, quotes
+                          - Presets: handwriting, ocr, monospace, proportional, allcaps
+    -k LAYOUTS           Comma-separated list of keyboard layouts. Only characters that are on the same
+                         key with the same modifier in all layouts (and no dead keys) are used
+                          - Layouts: us, uk, de, fr, ch, nordic
     -mL NUMBER           Minimum amount of lower-case characters (implies -L)
     -mN NUMBER           Minimum amount of numeric characters (implies -N)
     -mS NUMBER           Minimum amount of special characters (implies -S)
     -mU NUMBER           Minimum amount of upper-case characters (implies -U)
                           - Note: any of the "Minimum amount of" modes may result in
                             extraordinarily long calculation times
-                          - Note 2: The "minimum amount of" modes do not apply in
-                            pronounceable mode (-a 0)
+    -xL NUMBER           Maximum amount of lower-case characters
+    -xN NUMBER           Maximum amount of numeric characters
+    -xS NUMBER           Maximum amount of special characters
+    -xU NUMBER           Maximum amount of upper-case characters
+    -xR NUMBER           Maximum amount of identical consecutive characters (i. e. "aaa")
+    -xC NUMBER           Maximum amount of consecutive characters of the same character mode
+    -xQ NUMBER           Maximum length of sequential character runs (i. e. "abc", "321" or "qwe")
+    -fc [LUNS]           Character modes allowed for the first character of the password
+    -lc [LUNS]           Character modes allowed for the last character of the password
+                          - Note: i. e. "-fc LU" makes sure the password starts with a letter
+    -u                   Use every character only once in the password (Default: off)
+    -mC NUMBER           Minimum amount of different character modes in the password
+                          - Note: pronounceable passwords (-a 0) are regenerated until they
+                            meet the constraints (-xX, -mC, -fc, -lc and -u); strict
+                            constraints may fail with an error
+    -Q                   Generate passwords that pass the pam_pwquality rules configured in
+                         /etc/security/pwquality.conf (Default: off)
+    -Qf FILE             Like -Q, but read the pwquality settings from the given file
+                          - Note: pronounceable passwords (-a 0) are regenerated until they
+                            pass the rules; strict rules may fail with an error
+    -R RULES             Generate passwords that pass the given password rules in the "passwordrules"
+                         syntax (i. e. "required: upper; required: digit; minlength: 20;")
+    -D                   Generate passwords that meet the Active Directory complexity requirements
+                         (at least 3 different character modes) (Default: off)
+    -Du USER             sAMAccountName that must not be part of the password (implies -D)
+    -Dn NAME             Display name whose tokens must not be part of the password (implies -D)
+    -r                   Rotation mode: generate passwords that differ sufficiently from the previous
+                         password, which is read from the APG_PREVIOUS_PASSWORD environment variable
+                         or from the first line of stdin (Default: off)
+                          - Note: the new password is never a case or increment variant of the
+                            previous password
+    -rd NUMBER           Minimum Levenshtein distance to the previous password (Default: 4)
+    -rs NUMBER           Maximum length of a substring shared with the previous password (Default: 3)
     -C                   Enable complex password mode (implies -L -U -N -S and disables -H)
     -H                   Avoid ambiguous characters in passwords (i. e.: 1, l, I, O, 0) (Default: off)
+    -d                   Dictation-safe mode for passwords that are read aloud: avoids letters that
+                         sound alike (b/d/p/t/v/e/g, m/n and f/s), uses a single letter case and
+                         spells the generated passwords (Default: off)
+    -dc NUMBER           Append a check character after every NUMBER characters of the password
     -L                   Toggle lower-case characters in passwords (Default: on)
     -N                   Toggle numeric characters in passwords (Default: on)
     -S                   Toggle special characters in passwords (Default: off)
     -U                   Toggle upper-case characters in passwords (Default: on)
                           - Note: this flag has higher priority than the other old-style flags
     -l                   Spell generated passwords in phonetic alphabet (Default: off)
+                          - Note: applies to all algorithms; binary secrets (Algo: 3) are
+                            only spelled in their hex representation (-bh)
+    -sa ALPHABET         Spelling alphabet for spelled passwords (Default: nato)
+                          - Alphabets: nato, apco (police), din5009 (de), fr, it, es, nl
+                          - locale: select the alphabet based on $LC_ALL, $LC_MESSAGES or $LANG
+    -sr RENDERER         Output format of spelled passwords (Default: text)
+                          - text: names separated by "/" after the password
+                          - morse: international Morse code after the password; characters
+                            without a Morse code are spelled in brackets (i. e. [ASTERISK])
+                          - ssml: SSML document for text-to-speech systems on its own line
+                          - json: JSON array of {char, word, class} objects on its own line
     -t                   Spell generated pronounceable passwords with the corresponding 
                          syllables (Default: off)
+    -e                   Show the estimated entropy of the generated passwords (Default: off)
+    -o FORMAT            Output format of the generated secrets (Default: text)
+                          - json, ndjson, csv, yaml: one record per secret with the password,
+                            algorithm, length, estimated entropy, spelled (-l) and pronounced
+                            (-t) forms and the HIBP result (-p, null if not checked)
+    -oe CONTEXT          Quote and escape the generated passwords, so they can be pasted into the
+                         given context as is (Default: none)
+                          - Contexts: sh (POSIX shell), powershell, json, yaml, sql, xml (attribute
+                            value), url (percent-encoding)
+                          - Note: sql produces standard (ANSI) SQL literals that keep backslashes
+                            as is; use -sf sql for MySQL with backslash escapes enabled
+                          - Note: in the structured output formats (-o) the escaped password is
+                            an additional "escaped" field
+    -sf CONTEXT          Only use characters that need no escaping in the given context (see -oe),
+                         instead of escaping them. Lowers the entropy of the generated passwords
     -p                   Check the HIBP database if the generated passwords was found in a leak before (Default: off)
                           - Note: this feature requires internet connectivity
     -h                   Show this help text
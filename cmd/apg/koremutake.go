@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/wneessen/apg-go"
+)
+
+// koremutake implements the "koremutake" subcommand. Every given number is converted
+// into a Koremutake word and every other value is converted from a Koremutake word
+// back into a number. If no values are given, they are read line by line from stdin.
+// The exit code of the subcommand is returned
+func koremutake(args []string) int {
+	if len(args) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if value := strings.TrimSpace(scanner.Text()); value != "" {
+				args = append(args, value)
+			}
+		}
+	}
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "usage: apg koremutake <number|word>...")
+		return 1
+	}
+
+	exitCode := 0
+	for _, value := range args {
+		if number, err := strconv.ParseUint(value, 10, 64); err == nil {
+			fmt.Println(apg.KoremutakeEncode(number))
+			continue
+		}
+		number, err := apg.KoremutakeDecode(value)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to convert %q: %s\n", value, err)
+			exitCode = 1
+			continue
+		}
+		fmt.Println(number)
+	}
+	return exitCode
+}
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wneessen/apg-go"
+)
+
+// outputFormats lists the supported structured output formats
+var outputFormats = []string{"json", "ndjson", "csv", "yaml"}
+
+// secret represents a generated secret in the structured output formats
+type secret struct {
+	Password   string  `json:"password"`
+	Escaped    string  `json:"escaped,omitempty"`
+	Algorithm  string  `json:"algorithm"`
+	Length     int     `json:"length"`
+	Entropy    float64 `json:"entropy"`
+	Spelled    string  `json:"spelled"`
+	Pronounced string  `json:"pronounced"`
+	Pwned      *bool   `json:"pwned"`
+}
+
+// secretWriter writes generated secrets in one of the structured output formats
+type secretWriter struct {
+	format  string
+	output  io.Writer
+	csv     *csv.Writer
+	secrets []secret
+}
+
+// newSecretWriter returns a secretWriter for the given output format
+func newSecretWriter(output io.Writer, format string) (*secretWriter, error) {
+	writer := &secretWriter{format: strings.ToLower(format), output: output}
+	switch writer.format {
+	case "json", "ndjson", "yaml":
+	case "csv":
+		writer.csv = csv.NewWriter(output)
+		if err := writer.csv.Write([]string{
+			"password", "escaped", "algorithm", "length", "entropy", "spelled",
+			"pronounced", "pwned",
+		}); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown output format %q (supported: %s)", format,
+			strings.Join(outputFormats, ", "))
+	}
+	return writer, nil
+}
+
+// Write writes a single secret. In JSON format the secrets are collected and written
+// as a single array on Close
+func (w *secretWriter) Write(entry secret) error {
+	switch w.format {
+	case "json":
+		w.secrets = append(w.secrets, entry)
+		return nil
+	case "ndjson":
+		encoder := json.NewEncoder(w.output)
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(entry)
+	case "csv":
+		pwned := ""
+		if entry.Pwned != nil {
+			pwned = strconv.FormatBool(*entry.Pwned)
+		}
+		return w.csv.Write([]string{
+			entry.Password, entry.Escaped, entry.Algorithm, strconv.Itoa(entry.Length),
+			strconv.FormatFloat(entry.Entropy, 'f', 2, 64), entry.Spelled, entry.Pronounced, pwned,
+		})
+	default:
+		pwned := "null"
+		if entry.Pwned != nil {
+			pwned = strconv.FormatBool(*entry.Pwned)
+		}
+		escaped := ""
+		if entry.Escaped != "" {
+			escaped = "\n  escaped: " + strconv.Quote(entry.Escaped)
+		}
+		_, err := fmt.Fprintf(w.output, "- password: %s%s\n  algorithm: %s\n  length: %d\n"+
+			"  entropy: %.2f\n  spelled: %s\n  pronounced: %s\n  pwned: %s\n",
+			strconv.Quote(entry.Password), escaped, entry.Algorithm, entry.Length, entry.Entropy,
+			strconv.Quote(entry.Spelled), strconv.Quote(entry.Pronounced), pwned)
+		return err
+	}
+}
+
+// Close flushes the secrets that have not been written yet
+func (w *secretWriter) Close() error {
+	switch w.format {
+	case "json":
+		if w.secrets == nil {
+			w.secrets = []secret{}
+		}
+		encoder := json.NewEncoder(w.output)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(w.secrets)
+	case "csv":
+		w.csv.Flush()
+		return w.csv.Error()
+	default:
+		return nil
+	}
+}
+
+// generateStructured generates the configured amount of secrets and writes them in
+// the given structured output format to stdout. If an escape context is given, the
+// escaped password is part of the secrets
+func generateStructured(config *apg.Config, format string, escape apg.EscapeContext) error {
+	if config.Algorithm == apg.AlgoBinary && !config.BinaryHexMode {
+		return fmt.Errorf("structured output of binary secrets requires the hex representation (-bh)")
+	}
+	writer, err := newSecretWriter(os.Stdout, format)
+	if err != nil {
+		return err
+	}
+	generator := apg.New(config)
+	entropy, err := generator.Entropy()
+	if err != nil {
+		return fmt.Errorf("failed to calculate entropy: %w", err)
+	}
+
+	amount := config.NumberPass
+	if config.Algorithm == apg.AlgoBinary {
+		amount = 1
+	}
+	for i := int64(0); i < amount; i++ {
+		password, err := generator.Generate()
+		if err != nil {
+			return fmt.Errorf("failed to generate password: %w", err)
+		}
+		entry := secret{
+			Password:  password,
+			Algorithm: config.Algorithm.String(),
+			Length:    utf8.RuneCountInString(password),
+			Entropy:   math.Round(entropy*100) / 100,
+		}
+		if escape != apg.EscapeNone {
+			if entry.Escaped, err = apg.Escape(password, escape); err != nil {
+				return fmt.Errorf("failed to escape password: %w", err)
+			}
+		}
+		if config.SpellPassword {
+			if entry.Spelled, err = config.SpellingAlphabet.Render(password, config.SpellingRenderer); err != nil {
+				return fmt.Errorf("failed to spell password: %w", err)
+			}
+		}
+		if config.Algorithm == apg.AlgoPronounceable && config.SpellPronounceable {
+			if entry.Pronounced, err = generator.Pronounce(); err != nil {
+				return fmt.Errorf("failed to pronounce password: %w", err)
+			}
+		}
+		if config.CheckHIBP {
+			pwned, err := apg.HasBeenPwned(password)
+			switch {
+			case err != nil:
+				_, _ = fmt.Fprintf(os.Stderr, "failed to check HIBP database: %s\n", err)
+			default:
+				entry.Pwned = &pwned
+			}
+		}
+		if err = writer.Write(entry); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/wneessen/apg-go"
+)
+
+// profiles implements the "profiles" subcommand. The "list" command prints the
+// user-defined and built-in profiles with the estimated entropy of their passwords.
+// The exit code of the subcommand is returned
+func profiles(args []string) int {
+	if len(args) != 1 || args[0] != "list" {
+		_, _ = fmt.Fprintln(os.Stderr, "usage: apg profiles list")
+		return 1
+	}
+	list, err := loadProfiles()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to load profiles: %s\n", err)
+		return 1
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "NAME\tENTROPY\tDESCRIPTION")
+	listed := make(map[string]bool)
+	for _, profile := range list {
+		// User-defined profiles replace the built-in profiles of the same name
+		if listed[profile.Name] {
+			continue
+		}
+		listed[profile.Name] = true
+		config, err := profileConfig(profile)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to configure profile: %s\n", err)
+			return 1
+		}
+		entropy, err := apg.New(config).Entropy()
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to calculate entropy of profile %q: %s\n", profile.Name, err)
+			return 1
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%.2f bits\t%s\n", profile.Name, entropy, profile.Description)
+	}
+	if err = writer.Flush(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to write profiles: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// loadProfiles returns the user-defined profiles of the profiles file, followed by
+// the built-in profiles. The first profile of a name takes precedence
+func loadProfiles() ([]apg.Profile, error) {
+	path, err := userConfigFile(ProfilesFileEnv, profilesFileNames)
+	if err != nil {
+		return nil, err
+	}
+	var list []apg.Profile
+	if path != "" {
+		if list, err = apg.LoadProfiles(path); err != nil {
+			return nil, err
+		}
+	}
+	return append(list, apg.BuiltinProfiles()...), nil
+}
+
+// profileByName returns the user-defined or built-in profile with the given name
+func profileByName(name string) (apg.Profile, error) {
+	list, err := loadProfiles()
+	if err != nil {
+		return apg.Profile{}, err
+	}
+	return apg.ProfileByName(name, list...)
+}
+
+// profileConfig returns the config of the given profile on top of the defaults of
+// the configuration file and the environment
+func profileConfig(profile apg.Profile) (*apg.Config, error) {
+	config := apg.NewConfig(apg.WithAlgorithm(apg.AlgoRandom))
+	if err := configDefaults(config); err != nil {
+		return nil, err
+	}
+	if err := profile.Apply(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/wneessen/apg-go"
+)
+
+const (
+	// ConfigFileEnv is the name of the environment variable that holds the path of
+	// the configuration file. If it is set, the file has to exist
+	ConfigFileEnv = "APG_CONFIG"
+	// ProfilesFileEnv is the name of the environment variable that holds the path of
+	// the file with the user-defined profiles. If it is set, the file has to exist
+	ProfilesFileEnv = "APG_PROFILES"
+)
+
+var (
+	// configFileNames are the names of the configuration files in the apg-go directory
+	// of the user's configuration directory, in the order of their priority
+	configFileNames = []string{"config.toml", "config.json"}
+	// profilesFileNames are the names of the files with the user-defined profiles in
+	// the apg-go directory of the user's configuration directory, in the order of
+	// their priority
+	profilesFileNames = []string{"profiles.toml", "profiles.json"}
+)
+
+// configDefaults applies the settings of the configuration file and of the APG_*
+// environment variables to the given config. The environment variables take
+// precedence over the configuration file. Both are overridden by the CLI flags
+func configDefaults(config *apg.Config) error {
+	path, err := userConfigFile(ConfigFileEnv, configFileNames)
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		settings, err := apg.LoadSettingsFile(path)
+		if err != nil {
+			return err
+		}
+		if err = settings.Apply(config); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	settings, err := apg.SettingsFromEnv(os.Environ())
+	if err != nil {
+		return err
+	}
+	if err = settings.Apply(config); err != nil {
+		return fmt.Errorf("environment: %w", err)
+	}
+	return nil
+}
+
+// userConfigFile returns the path of a configuration file. The path of the given
+// environment variable has the highest priority, otherwise the first existing file
+// of the given names in the apg-go directory of the user's configuration directory
+// ($XDG_CONFIG_HOME or ~/.config on Linux) is used. If no file exists, an empty
+// path is returned
+func userConfigFile(env string, names []string) (string, error) {
+	if path := os.Getenv(env); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("failed to read config file: %w", err)
+		}
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", nil
+	}
+	for _, name := range names {
+		path := filepath.Join(dir, "apg-go", name)
+		_, err = os.Stat(path)
+		switch {
+		case err == nil:
+			return path, nil
+		case !errors.Is(err, fs.ErrNotExist):
+			return "", fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// unspell implements the "unspell" subcommand. The given words of a spelling alphabet
+// are converted back into the spelled password. If no words are given, spelled
+// passwords are read line by line from stdin. The exit code of the subcommand is
+// returned
+func unspell(args []string) int {
+	flags := flag.NewFlagSet("unspell", flag.ContinueOnError)
+	alphabetName := flags.String("sa", "nato", "")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(os.Stderr, "usage: apg unspell [-sa alphabet] [word]...")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	alphabet, err := spellingAlphabetFromFlag(*alphabetName)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to select spelling alphabet: %s\n", err)
+		return 1
+	}
+
+	inputs := []string{strings.Join(flags.Args(), " ")}
+	if flags.NArg() == 0 {
+		inputs = inputs[:0]
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if value := strings.TrimSpace(scanner.Text()); value != "" {
+				inputs = append(inputs, value)
+			}
+		}
+	}
+	if len(inputs) == 0 {
+		flags.Usage()
+		return 1
+	}
+
+	exitCode := 0
+	for _, input := range inputs {
+		password, err := alphabet.Unspell(input)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to unspell %q: %s\n", input, err)
+			exitCode = 1
+			continue
+		}
+		fmt.Println(password)
+	}
+	return exitCode
+}
@@ -28,17 +28,83 @@ type Config struct {
 	BinaryHexMode bool
 	// BinaryNewline if set will print out a new line in AlgoBinary mode
 	BinaryNewline bool
+	// Capitalization sets the style that is used to convert letters of the syllables
+	// of pronounceable passwords to upper-case
+	Capitalization CapitalizationStyle
+	// CharClasses is a list of custom, user-named character classes whose characters
+	// are added to the character range of generated passwords
+	CharClasses []CharClass
+	// CharRanges holds custom character ranges that replace the built-in character
+	// range of the corresponding character Mode
+	CharRanges map[Mode]string
+	// CheckCharGroupSize sets the size of the groups of a generated password that
+	// are followed by a check character. A zero value disables the check characters
+	CheckCharGroupSize int64
 	// CheckHIBP sets a flag if the generated password has to be checked
 	// against the HIBP pwned password database
 	CheckHIBP bool
+	// ClassWeights holds the relative weights (in percent) of the character modes in
+	// generated passwords. See ClassWeightsFromString for details. Characters of
+	// custom character classes that are not part of any mode use the
+	// ClassWeightOther key
+	ClassWeights map[Mode]float64
+	// Confusables holds the groups of confusable characters that should be avoided
+	// in generated passwords. Single characters of the groups are excluded and
+	// multi-character sequences (i. e. "rn") are not allowed in the password
+	Confusables ConfusableMask
 	// ExcludeChars is a list of characters that should be excluded from
 	// generated passwords
 	ExcludeChars string
+	// Filters is a list of Filter functions that every generated password has to
+	// pass. See WithFilters for details
+	Filters []Filter
+	// FirstCharMode restricts the first character of a generated password to
+	// the character modes set in the bitmask. A zero value allows any character
+	FirstCharMode ModeMask
 	// FixedLength sets a fixed length for generated passwords and ignores
 	// the MinLength and MaxLength values
 	FixedLength int64
+	// ForbiddenWords is a list of words that must not be part of generated passwords.
+	// The check is case-insensitive
+	ForbiddenWords []string
+	// KeyboardLayouts restricts the characters of generated passwords to the characters
+	// that are on the same key with the same modifier in all of the keyboard layouts
+	KeyboardLayouts []KeyboardLayout
+	// LastCharMode restricts the last character of a generated password to
+	// the character modes set in the bitmask. A zero value allows any character
+	LastCharMode ModeMask
+	// LengthStrategy sets the strategy that is used to meet the requested password
+	// length in pronounceable password mode
+	LengthStrategy LengthStrategy
+	// Leetspeak if set will randomly substitute letters of the syllables of
+	// pronounceable passwords with similar looking numbers (i. e. "e" with "3")
+	Leetspeak bool
+	// MaxClassRepeat sets the maximum amount of consecutive characters of the same
+	// character mode in a generated password
+	MaxClassRepeat int64
 	// MaxLength sets the maximum length for a generated password
 	MaxLength int64
+	// MaxLowerCase represents the maximum amount of lower-case characters that can
+	// be part of the generated password
+	MaxLowerCase int64
+	// MaxNumeric represents the maximum amount of numeric characters that can
+	// be part of the generated password
+	MaxNumeric int64
+	// MaxRepeat sets the maximum amount of identical consecutive characters
+	// in a generated password
+	MaxRepeat int64
+	// MaxSequence sets the maximum length of a sequential run of characters
+	// (i. e. "abc", "321" or keyboard walks like "qwe") in a generated password
+	MaxSequence int64
+	// MaxSpecial represents the maximum amount of special characters that can
+	// be part of the generated password
+	MaxSpecial int64
+	// MaxUpperCase represents the maximum amount of upper-case characters that can
+	// be part of the generated password
+	MaxUpperCase int64
+	// MinClasses sets the minimum amount of different character modes that have
+	// to be part of the generated password
+	MinClasses int64
 	// MinLength sets the minimum length for a generated password
 	MinLength int64
 	// MinLowerCase represents the minimum amount of lower-case characters that have
@@ -61,16 +127,44 @@ type Config struct {
 	// NumberPass sets the number of passwords that are generated
 	// and returned by the generator
 	NumberPass int64
+	// PasswordRules holds password rules in the "passwordrules" syntax that every
+	// generated password has to pass. See WithPasswordRules for details
+	PasswordRules *PasswordRules
+	// PWQuality holds libpwquality settings that every generated password has
+	// to pass. See WithPWQuality for details
+	PWQuality *PWQuality
+	// SafeFor drops all characters from the character range of generated passwords
+	// that would have to be escaped in the EscapeContext
+	SafeFor EscapeContext
 	// SpellPassword if set will spell the generated passwords in the phonetic alphabet
 	SpellPassword bool
 	// SpellPronounceable if set will spell the generated pronounceable passwords in
 	// as its corresponding syllables
 	SpellPronounceable bool
+	// SpellingAlphabet is the spelling alphabet that is used to spell passwords. If
+	// the alphabet is empty, the NATO alphabet is used
+	SpellingAlphabet SpellingAlphabet
+	// SpellingRenderer sets the output format of spelled passwords
+	SpellingRenderer SpellingRenderer
+	// SyllableSet is the set of syllables that is used for pronounceable passwords.
+	// If the set is empty, the Koremutake syllables are used
+	SyllableSet SyllableSet
+	// SyllableSeparator is placed between the syllables of pronounceable passwords.
+	// The separator counts towards the password length
+	SyllableSeparator string
+	// UniqueChars if set will make sure that every character of the generated
+	// password is only used once
+	UniqueChars bool
 }
 
 // Option is a function that can override default Config settings
 type Option func(*Config)
 
+// Filter is a function that is applied to every password returned by the
+// generator. If the password does not pass the filter, an error is returned
+// and the generator will generate a new password
+type Filter func(password string) error
+
 // NewConfig creates a new Config instance and pre-fills it with sane
 // default settings. The Config is returned as pointer value
 func NewConfig(opts ...Option) *Config {
@@ -105,6 +199,68 @@ func WithBinaryHexMode() Option {
 	}
 }
 
+// WithCapitalization sets the style that is used to convert letters of the syllables
+// of pronounceable passwords to upper-case
+func WithCapitalization(style CapitalizationStyle) Option {
+	return func(config *Config) {
+		config.Capitalization = style
+	}
+}
+
+// WithCharClass adds a custom, user-named character class to the configuration.
+// The characters of the class are added to the character range of the generated
+// passwords and every password will contain at least minAmount of them
+func WithCharClass(name, chars string, minAmount int64) Option {
+	return func(config *Config) {
+		config.CharClasses = append(config.CharClasses, CharClass{
+			Chars:     chars,
+			MinAmount: minAmount,
+			Name:      name,
+		})
+	}
+}
+
+// WithCharRange replaces the built-in character range of the given character Mode
+// with the given characters. The custom range is used regardless of the
+// human-readable setting
+func WithCharRange(mode Mode, chars string) Option {
+	return func(config *Config) {
+		if config.CharRanges == nil {
+			config.CharRanges = make(map[Mode]string)
+		}
+		config.CharRanges[mode] = chars
+	}
+}
+
+// WithConfusables adds the given groups of confusable characters to the groups that
+// should be avoided in generated passwords
+func WithConfusables(mask ConfusableMask) Option {
+	return func(config *Config) {
+		config.Confusables |= mask
+	}
+}
+
+// WithCheckChars splits the generated passwords into groups of the given size and
+// appends a check character to each group. See AddCheckChars for details
+func WithCheckChars(groupSize int64) Option {
+	return func(config *Config) {
+		config.CheckCharGroupSize = groupSize
+	}
+}
+
+// WithClassWeight sets the weight (in percent) of the given character Mode in the
+// generated passwords. Instead of being proportional to the size of the character
+// ranges, the given percentage of the password characters will be of the given Mode
+// in expectation. Modes without a weight share the remaining percentage
+func WithClassWeight(mode Mode, weight float64) Option {
+	return func(config *Config) {
+		if config.ClassWeights == nil {
+			config.ClassWeights = make(map[Mode]float64)
+		}
+		config.ClassWeights[mode] = weight
+	}
+}
+
 // WithExcludeChars sets a list of characters to be excluded in the generated
 // passwords
 func WithExcludeChars(chars string) Option {
@@ -113,6 +269,31 @@ func WithExcludeChars(chars string) Option {
 	}
 }
 
+// WithForbiddenWords adds a list of words that must not be part of the generated
+// passwords. The check is case-insensitive
+func WithForbiddenWords(words ...string) Option {
+	return func(config *Config) {
+		config.ForbiddenWords = append(config.ForbiddenWords, words...)
+	}
+}
+
+// WithFilters adds a list of Filter functions that the generated passwords have
+// to pass. Passwords that do not pass all filters are discarded and generated
+// again
+func WithFilters(filters ...Filter) Option {
+	return func(config *Config) {
+		config.Filters = append(config.Filters, filters...)
+	}
+}
+
+// WithFirstCharMode restricts the first character of the generated passwords
+// to the given character modes
+func WithFirstCharMode(mask ModeMask) Option {
+	return func(config *Config) {
+		config.FirstCharMode = mask
+	}
+}
+
 // WithFixedLength sets a fixed password length
 func WithFixedLength(length int64) Option {
 	return func(config *Config) {
@@ -120,6 +301,47 @@ func WithFixedLength(length int64) Option {
 	}
 }
 
+// WithKeyboardLayouts restricts the characters of the generated passwords to the
+// characters that are on the same key with the same modifier in all of the given
+// keyboard layouts
+func WithKeyboardLayouts(layouts ...KeyboardLayout) Option {
+	return func(config *Config) {
+		config.KeyboardLayouts = append(config.KeyboardLayouts, layouts...)
+	}
+}
+
+// WithLastCharMode restricts the last character of the generated passwords
+// to the given character modes
+func WithLastCharMode(mask ModeMask) Option {
+	return func(config *Config) {
+		config.LastCharMode = mask
+	}
+}
+
+// WithLengthStrategy sets the strategy that is used to meet the requested password
+// length in pronounceable password mode
+func WithLengthStrategy(strategy LengthStrategy) Option {
+	return func(config *Config) {
+		config.LengthStrategy = strategy
+	}
+}
+
+// WithLeetspeak enables the random leetspeak substitution of letters in the
+// syllables of pronounceable passwords
+func WithLeetspeak() Option {
+	return func(config *Config) {
+		config.Leetspeak = true
+	}
+}
+
+// WithMinClasses sets the minimum amount of different character modes that the
+// generated password should contain
+func WithMinClasses(amount int64) Option {
+	return func(config *Config) {
+		config.MinClasses = amount
+	}
+}
+
 // WithMinLength overrides the minimum password length
 func WithMinLength(length int64) Option {
 	return func(config *Config) {
@@ -178,6 +400,62 @@ func WithMaxLength(length int64) Option {
 	}
 }
 
+// WithMaxClassRepeat sets the maximum amount of consecutive characters of the
+// same character mode that the generated password can contain
+func WithMaxClassRepeat(amount int64) Option {
+	return func(config *Config) {
+		config.MaxClassRepeat = amount
+	}
+}
+
+// WithMaxLowercase sets the maximum amount of lowercase characters that
+// the generated password can contain
+func WithMaxLowercase(amount int64) Option {
+	return func(config *Config) {
+		config.MaxLowerCase = amount
+	}
+}
+
+// WithMaxNumeric sets the maximum amount of numeric characters that
+// the generated password can contain
+func WithMaxNumeric(amount int64) Option {
+	return func(config *Config) {
+		config.MaxNumeric = amount
+	}
+}
+
+// WithMaxRepeat sets the maximum amount of identical consecutive characters
+// that the generated password can contain
+func WithMaxRepeat(amount int64) Option {
+	return func(config *Config) {
+		config.MaxRepeat = amount
+	}
+}
+
+// WithMaxSequence sets the maximum length of sequential character runs (like
+// "abc", "123" or "qwe") that the generated password can contain
+func WithMaxSequence(length int64) Option {
+	return func(config *Config) {
+		config.MaxSequence = length
+	}
+}
+
+// WithMaxSpecial sets the maximum amount of special characters that
+// the generated password can contain
+func WithMaxSpecial(amount int64) Option {
+	return func(config *Config) {
+		config.MaxSpecial = amount
+	}
+}
+
+// WithMaxUppercase sets the maximum amount of uppercase characters that
+// the generated password can contain
+func WithMaxUppercase(amount int64) Option {
+	return func(config *Config) {
+		config.MaxUpperCase = amount
+	}
+}
+
 // WithMobileGrouping enables the mobile-friendly character grouping for AlgoRandom
 func WithMobileGrouping() Option {
 	return func(config *Config) {
@@ -191,3 +469,48 @@ func WithModeMask(mask ModeMask) Option {
 		config.Mode = mask
 	}
 }
+
+// WithSafeFor makes sure that generated passwords only consist of characters that
+// can be used in the given EscapeContext without escaping
+func WithSafeFor(context EscapeContext) Option {
+	return func(config *Config) {
+		config.SafeFor = context
+	}
+}
+
+// WithSpellingAlphabet sets the spelling alphabet that is used to spell passwords
+func WithSpellingAlphabet(alphabet SpellingAlphabet) Option {
+	return func(config *Config) {
+		config.SpellingAlphabet = alphabet
+	}
+}
+
+// WithSpellingRenderer sets the output format of spelled passwords
+func WithSpellingRenderer(renderer SpellingRenderer) Option {
+	return func(config *Config) {
+		config.SpellingRenderer = renderer
+	}
+}
+
+// WithSyllableSet sets the set of syllables that is used for pronounceable passwords
+func WithSyllableSet(set SyllableSet) Option {
+	return func(config *Config) {
+		config.SyllableSet = set
+	}
+}
+
+// WithSyllableSeparator sets the separator that is placed between the syllables of
+// pronounceable passwords
+func WithSyllableSeparator(separator string) Option {
+	return func(config *Config) {
+		config.SyllableSeparator = separator
+	}
+}
+
+// WithUniqueChars makes sure that every character is used only once in the
+// generated password
+func WithUniqueChars() Option {
+	return func(config *Config) {
+		config.UniqueChars = true
+	}
+}
@@ -209,6 +209,292 @@ func TestWithModeMask(t *testing.T) {
 	}
 }
 
+func TestWithFirstCharMode(t *testing.T) {
+	e := ModeLowerCase | ModeUpperCase
+	c := NewConfig(WithFirstCharMode(ModeMask(e)))
+	if c == nil {
+		t.Errorf("NewConfig(WithFirstCharMode()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.FirstCharMode != ModeMask(e) {
+		t.Errorf("NewConfig(WithFirstCharMode()) failed, expected mask: %d, got: %d",
+			e, c.FirstCharMode)
+	}
+}
+
+func TestWithLastCharMode(t *testing.T) {
+	e := ModeNumeric
+	c := NewConfig(WithLastCharMode(ModeMask(e)))
+	if c == nil {
+		t.Errorf("NewConfig(WithLastCharMode()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.LastCharMode != ModeMask(e) {
+		t.Errorf("NewConfig(WithLastCharMode()) failed, expected mask: %d, got: %d",
+			e, c.LastCharMode)
+	}
+}
+
+func TestWithMaxAmounts(t *testing.T) {
+	c := NewConfig(WithMaxLowercase(1), WithMaxNumeric(2), WithMaxSpecial(3),
+		WithMaxUppercase(4), WithMaxRepeat(5), WithMaxSequence(6))
+	if c == nil {
+		t.Errorf("NewConfig(WithMaxX()) failed, expected config pointer but got nil")
+		return
+	}
+	tests := []struct {
+		name string
+		got  int64
+		want int64
+	}{
+		{"MaxLowerCase", c.MaxLowerCase, 1},
+		{"MaxNumeric", c.MaxNumeric, 2},
+		{"MaxSpecial", c.MaxSpecial, 3},
+		{"MaxUpperCase", c.MaxUpperCase, 4},
+		{"MaxRepeat", c.MaxRepeat, 5},
+		{"MaxSequence", c.MaxSequence, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("NewConfig(WithMaxX()) failed, expected %s: %d, got: %d",
+					tt.name, tt.want, tt.got)
+			}
+		})
+	}
+}
+
+func TestWithMinClasses(t *testing.T) {
+	var e int64 = 3
+	c := NewConfig(WithMinClasses(e))
+	if c == nil {
+		t.Errorf("NewConfig(WithMinClasses()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.MinClasses != e {
+		t.Errorf("NewConfig(WithMinClasses()) failed, expected min classes: %d, got: %d",
+			e, c.MinClasses)
+	}
+}
+
+func TestWithMaxClassRepeat(t *testing.T) {
+	var e int64 = 2
+	c := NewConfig(WithMaxClassRepeat(e))
+	if c == nil {
+		t.Errorf("NewConfig(WithMaxClassRepeat()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.MaxClassRepeat != e {
+		t.Errorf("NewConfig(WithMaxClassRepeat()) failed, expected max class repeat: %d, got: %d",
+			e, c.MaxClassRepeat)
+	}
+}
+
+func TestWithUniqueChars(t *testing.T) {
+	c := NewConfig(WithUniqueChars())
+	if c == nil {
+		t.Errorf("NewConfig(WithUniqueChars()) failed, expected config pointer but got nil")
+		return
+	}
+	if !c.UniqueChars {
+		t.Errorf("NewConfig(WithUniqueChars()) failed, expected: %t, got: %t",
+			true, c.UniqueChars)
+	}
+}
+
+func TestWithForbiddenWords(t *testing.T) {
+	c := NewConfig(WithForbiddenWords("foo"), WithForbiddenWords("bar", "baz"))
+	if c == nil {
+		t.Errorf("NewConfig(WithForbiddenWords()) failed, expected config pointer but got nil")
+		return
+	}
+	if len(c.ForbiddenWords) != 3 {
+		t.Errorf("NewConfig(WithForbiddenWords()) failed, expected: %d words, got: %d",
+			3, len(c.ForbiddenWords))
+	}
+}
+
+func TestWithFilters(t *testing.T) {
+	filter := func(string) error { return nil }
+	c := NewConfig(WithFilters(filter), WithFilters(filter, filter))
+	if c == nil {
+		t.Errorf("NewConfig(WithFilters()) failed, expected config pointer but got nil")
+		return
+	}
+	if len(c.Filters) != 3 {
+		t.Errorf("NewConfig(WithFilters()) failed, expected: %d filters, got: %d",
+			3, len(c.Filters))
+	}
+}
+
+func TestWithCharClass(t *testing.T) {
+	c := NewConfig(WithCharClass("brackets", "()[]", 2))
+	if c == nil {
+		t.Errorf("NewConfig(WithCharClass()) failed, expected config pointer but got nil")
+		return
+	}
+	if len(c.CharClasses) != 1 {
+		t.Fatalf("NewConfig(WithCharClass()) failed, expected: %d classes, got: %d", 1, len(c.CharClasses))
+	}
+	class := c.CharClasses[0]
+	if class.Name != "brackets" || class.Chars != "()[]" || class.MinAmount != 2 {
+		t.Errorf("NewConfig(WithCharClass()) failed, unexpected class: %+v", class)
+	}
+}
+
+func TestWithCharRange(t *testing.T) {
+	c := NewConfig(WithCharRange(ModeSpecial, "!?"))
+	if c == nil {
+		t.Errorf("NewConfig(WithCharRange()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.CharRanges[ModeSpecial] != "!?" {
+		t.Errorf("NewConfig(WithCharRange()) failed, expected: %s, got: %s", "!?", c.CharRanges[ModeSpecial])
+	}
+}
+
+func TestWithConfusables(t *testing.T) {
+	c := NewConfig(WithConfusables(ConfusableMask(ConfusableZero)), WithConfusables(ConfusablePresetMonospace))
+	if c == nil {
+		t.Errorf("NewConfig(WithConfusables()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.Confusables != ConfusablePresetMonospace {
+		t.Errorf("NewConfig(WithConfusables()) failed, expected: %d, got: %d", ConfusablePresetMonospace,
+			c.Confusables)
+	}
+}
+
+func TestWithKeyboardLayouts(t *testing.T) {
+	c := NewConfig(WithKeyboardLayouts(KeyboardLayoutUS, KeyboardLayoutDE))
+	if c == nil {
+		t.Errorf("NewConfig(WithKeyboardLayouts()) failed, expected config pointer but got nil")
+		return
+	}
+	if len(c.KeyboardLayouts) != 2 {
+		t.Errorf("NewConfig(WithKeyboardLayouts()) failed, expected: %d layouts, got: %d", 2,
+			len(c.KeyboardLayouts))
+	}
+}
+
+func TestWithCheckChars(t *testing.T) {
+	c := NewConfig(WithCheckChars(4))
+	if c == nil {
+		t.Errorf("NewConfig(WithCheckChars()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.CheckCharGroupSize != 4 {
+		t.Errorf("NewConfig(WithCheckChars()) failed, expected: %d, got: %d", 4, c.CheckCharGroupSize)
+	}
+}
+
+func TestWithClassWeight(t *testing.T) {
+	c := NewConfig(WithClassWeight(ModeSpecial, 10), WithClassWeight(ModeNumeric, 20))
+	if c == nil {
+		t.Errorf("NewConfig(WithClassWeight()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.ClassWeights[ModeSpecial] != 10 {
+		t.Errorf("NewConfig(WithClassWeight()) failed, expected: %f, got: %f", 10.0, c.ClassWeights[ModeSpecial])
+	}
+	if c.ClassWeights[ModeNumeric] != 20 {
+		t.Errorf("NewConfig(WithClassWeight()) failed, expected: %f, got: %f", 20.0, c.ClassWeights[ModeNumeric])
+	}
+}
+
+func TestWithLengthStrategy(t *testing.T) {
+	c := NewConfig(WithLengthStrategy(LengthStrategyExact))
+	if c == nil {
+		t.Errorf("NewConfig(WithLengthStrategy()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.LengthStrategy != LengthStrategyExact {
+		t.Errorf("NewConfig(WithLengthStrategy()) failed, expected: %s, got: %s", LengthStrategyExact,
+			c.LengthStrategy)
+	}
+}
+
+func TestWithCapitalization(t *testing.T) {
+	c := NewConfig(WithCapitalization(CapitalizationFirstSyllable))
+	if c == nil {
+		t.Errorf("NewConfig(WithCapitalization()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.Capitalization != CapitalizationFirstSyllable {
+		t.Errorf("NewConfig(WithCapitalization()) failed, expected: %s, got: %s", CapitalizationFirstSyllable,
+			c.Capitalization)
+	}
+}
+
+func TestWithLeetspeak(t *testing.T) {
+	c := NewConfig(WithLeetspeak())
+	if c == nil {
+		t.Errorf("NewConfig(WithLeetspeak()) failed, expected config pointer but got nil")
+		return
+	}
+	if !c.Leetspeak {
+		t.Errorf("NewConfig(WithLeetspeak()) failed, expected: %t, got: %t", true, c.Leetspeak)
+	}
+}
+
+func TestWithSyllableSeparator(t *testing.T) {
+	c := NewConfig(WithSyllableSeparator("-"))
+	if c == nil {
+		t.Errorf("NewConfig(WithSyllableSeparator()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.SyllableSeparator != "-" {
+		t.Errorf("NewConfig(WithSyllableSeparator()) failed, expected: %s, got: %s", "-", c.SyllableSeparator)
+	}
+}
+
+func TestWithSafeFor(t *testing.T) {
+	c := NewConfig(WithSafeFor(EscapeShell))
+	if c == nil {
+		t.Errorf("NewConfig(WithSafeFor()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.SafeFor != EscapeShell {
+		t.Errorf("NewConfig(WithSafeFor()) failed, expected: %s, got: %s", EscapeShell, c.SafeFor)
+	}
+}
+
+func TestWithSpellingAlphabet(t *testing.T) {
+	c := NewConfig(WithSpellingAlphabet(SpellingAlphabetDIN5009))
+	if c == nil {
+		t.Errorf("NewConfig(WithSpellingAlphabet()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.SpellingAlphabet.Name != SpellingAlphabetDIN5009.Name {
+		t.Errorf("NewConfig(WithSpellingAlphabet()) failed, expected: %s, got: %s",
+			SpellingAlphabetDIN5009.Name, c.SpellingAlphabet.Name)
+	}
+}
+
+func TestWithSpellingRenderer(t *testing.T) {
+	c := NewConfig(WithSpellingRenderer(SpellingRendererSSML))
+	if c == nil {
+		t.Errorf("NewConfig(WithSpellingRenderer()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.SpellingRenderer != SpellingRendererSSML {
+		t.Errorf("NewConfig(WithSpellingRenderer()) failed, expected: %s, got: %s",
+			SpellingRendererSSML, c.SpellingRenderer)
+	}
+}
+
+func TestWithSyllableSet(t *testing.T) {
+	c := NewConfig(WithSyllableSet(SyllableSetGerman))
+	if c == nil {
+		t.Errorf("NewConfig(WithSyllableSet()) failed, expected config pointer but got nil")
+		return
+	}
+	if c.SyllableSet.Name != SyllableSetGerman.Name {
+		t.Errorf("NewConfig(WithSyllableSet()) failed, expected: %s, got: %s", SyllableSetGerman.Name,
+			c.SyllableSet.Name)
+	}
+}
+
 func FuzzWithAlgorithm(f *testing.F) {
 	f.Add(0)
 	f.Add(1)
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Confusable represents a group of characters (or character sequences) that can
+// easily be confused with each other
+type Confusable uint16
+
+// ConfusableMask represents a bitmask of confusable groups
+type ConfusableMask uint16
+
+const (
+	// ConfusableZero represents the "0Oo" group
+	ConfusableZero Confusable = 1 << iota
+	// ConfusableOne represents the "1lI|" group
+	ConfusableOne
+	// ConfusableFive represents the "5S" group
+	ConfusableFive
+	// ConfusableTwo represents the "2Z" group
+	ConfusableTwo
+	// ConfusableEight represents the "8B" group
+	ConfusableEight
+	// ConfusableRNM represents the "rn/m" group
+	ConfusableRNM
+	// ConfusableVVW represents the "vv/w" group
+	ConfusableVVW
+	// ConfusableQuotes represents the group of quotes and backticks
+	ConfusableQuotes
+)
+
+const (
+	// ConfusablePresetAllCaps holds the confusable groups that are relevant when passwords
+	// are printed in all-caps
+	ConfusablePresetAllCaps = ConfusableMask(ConfusableZero | ConfusableOne | ConfusableFive |
+		ConfusableTwo | ConfusableEight)
+	// ConfusablePresetHandwriting holds the confusable groups that are relevant when
+	// passwords are written down by hand
+	ConfusablePresetHandwriting = ConfusableMask(ConfusableZero | ConfusableOne | ConfusableFive |
+		ConfusableTwo | ConfusableEight | ConfusableRNM | ConfusableVVW | ConfusableQuotes)
+	// ConfusablePresetMonospace holds the confusable groups that are relevant when passwords
+	// are displayed in a monospace font
+	ConfusablePresetMonospace = ConfusableMask(ConfusableZero | ConfusableOne | ConfusableQuotes)
+	// ConfusablePresetOCR holds the confusable groups that are relevant when passwords are
+	// read by optical character recognition
+	ConfusablePresetOCR = ConfusableMask(ConfusableZero | ConfusableOne | ConfusableFive |
+		ConfusableEight | ConfusableRNM | ConfusableVVW | ConfusableQuotes)
+	// ConfusablePresetProportional holds the confusable groups that are relevant when
+	// passwords are displayed in a proportional font
+	ConfusablePresetProportional = ConfusableMask(ConfusableZero | ConfusableOne | ConfusableRNM |
+		ConfusableVVW | ConfusableQuotes)
+)
+
+// ErrUnknownConfusable is returned if a confusable group or preset name is unknown
+var ErrUnknownConfusable = errors.New("unknown confusable group or preset")
+
+// confusables is the list of all confusable groups
+var confusables = []Confusable{
+	ConfusableZero, ConfusableOne, ConfusableFive, ConfusableTwo, ConfusableEight,
+	ConfusableRNM, ConfusableVVW, ConfusableQuotes,
+}
+
+// confusablePresets maps the names of the confusable presets to their bitmask
+var confusablePresets = map[string]ConfusableMask{
+	"allcaps":      ConfusablePresetAllCaps,
+	"handwriting":  ConfusablePresetHandwriting,
+	"monospace":    ConfusablePresetMonospace,
+	"ocr":          ConfusablePresetOCR,
+	"proportional": ConfusablePresetProportional,
+}
+
+// Members returns the characters and character sequences of the confusable group
+func (c Confusable) Members() []string {
+	switch c {
+	case ConfusableZero:
+		return []string{"0", "O", "o"}
+	case ConfusableOne:
+		return []string{"1", "l", "I", "|"}
+	case ConfusableFive:
+		return []string{"5", "S"}
+	case ConfusableTwo:
+		return []string{"2", "Z"}
+	case ConfusableEight:
+		return []string{"8", "B"}
+	case ConfusableRNM:
+		return []string{"rn", "m"}
+	case ConfusableVVW:
+		return []string{"vv", "w", "VV", "W"}
+	case ConfusableQuotes:
+		return []string{"'", `"`, "`"}
+	default:
+		return nil
+	}
+}
+
+// String satisfies the fmt.Stringer interface for the Confusable type
+func (c Confusable) String() string {
+	switch c {
+	case ConfusableZero:
+		return "0Oo"
+	case ConfusableOne:
+		return "1lI|"
+	case ConfusableFive:
+		return "5S"
+	case ConfusableTwo:
+		return "2Z"
+	case ConfusableEight:
+		return "8B"
+	case ConfusableRNM:
+		return "rn/m"
+	case ConfusableVVW:
+		return "vv/w"
+	case ConfusableQuotes:
+		return "quotes"
+	default:
+		return "Unknown"
+	}
+}
+
+// MaskSetConfusable sets a specific Confusable group to a given ConfusableMask
+func MaskSetConfusable(mask ConfusableMask, group Confusable) ConfusableMask {
+	return ConfusableMask(uint16(mask) | uint16(group))
+}
+
+// MaskHasConfusable returns true if a given ConfusableMask holds a specific Confusable group
+func MaskHasConfusable(mask ConfusableMask, group Confusable) bool {
+	return uint16(mask)&uint16(group) != 0
+}
+
+// ConfusablesFromString parses a comma-separated list of confusable group names (i. e.
+// "0Oo" or "rn/m") and preset names (i. e. "handwriting" or "ocr") and returns the
+// corresponding ConfusableMask. If a name is unknown, an error wrapping
+// ErrUnknownConfusable is returned
+func ConfusablesFromString(names string) (ConfusableMask, error) {
+	var mask ConfusableMask
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if preset, ok := confusablePresets[strings.ToLower(name)]; ok {
+			mask |= preset
+			continue
+		}
+		found := false
+		for _, group := range confusables {
+			if group.String() == name {
+				mask = MaskSetConfusable(mask, group)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("%w: %q", ErrUnknownConfusable, name)
+		}
+	}
+	return mask, nil
+}
+
+// confusableChars returns the Charset of all single characters of the confusable groups
+// set in the given ConfusableMask. These characters are excluded from generated passwords
+func confusableChars(mask ConfusableMask) Charset {
+	var chars strings.Builder
+	for _, group := range confusables {
+		if !MaskHasConfusable(mask, group) {
+			continue
+		}
+		for _, member := range group.Members() {
+			if len([]rune(member)) == 1 {
+				chars.WriteString(member)
+			}
+		}
+	}
+	return NewCharset(chars.String())
+}
+
+// containsConfusableSequence returns true if the given password contains any of the
+// multi-character sequences (i. e. "rn") of the confusable groups set in the given
+// ConfusableMask
+func containsConfusableSequence(password string, mask ConfusableMask) bool {
+	for _, group := range confusables {
+		if !MaskHasConfusable(mask, group) {
+			continue
+		}
+		for _, member := range group.Members() {
+			if len([]rune(member)) > 1 && strings.Contains(password, member) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfusablesFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   string
+		want    ConfusableMask
+		wantErr bool
+	}{
+		{"Empty", "", 0, false},
+		{"Single group", "0Oo", ConfusableMask(ConfusableZero), false},
+		{"Multiple groups", "1lI|, rn/m", ConfusableMask(ConfusableOne | ConfusableRNM), false},
+		{"Preset", "monospace", ConfusablePresetMonospace, false},
+		{"Preset case-insensitive", "OCR", ConfusablePresetOCR, false},
+		{"Preset and group", "allcaps,quotes", ConfusablePresetAllCaps | ConfusableMask(ConfusableQuotes), false},
+		{"Unknown group", "0Oo,foo", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := ConfusablesFromString(tt.names)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownConfusable) {
+					t.Errorf("ConfusablesFromString() failed, expected error: %s, got: %s",
+						ErrUnknownConfusable, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConfusablesFromString() failed: %s", err)
+			}
+			if mask != tt.want {
+				t.Errorf("ConfusablesFromString() failed, expected: %d, got: %d", tt.want, mask)
+			}
+		})
+	}
+}
+
+func TestConfusable_String(t *testing.T) {
+	for _, group := range confusables {
+		mask, err := ConfusablesFromString(group.String())
+		if err != nil {
+			t.Errorf("ConfusablesFromString(%s) failed: %s", group, err)
+		}
+		if !MaskHasConfusable(mask, group) {
+			t.Errorf("ConfusablesFromString(%s) failed, expected group to be set", group)
+		}
+		if len(group.Members()) < 2 {
+			t.Errorf("Members() of group %s failed, expected at least 2 members", group)
+		}
+	}
+	if Confusable(0).String() != "Unknown" || Confusable(0).Members() != nil {
+		t.Errorf("unknown confusable group is not supposed to have a name or members")
+	}
+}
+
+func TestConfusableChars(t *testing.T) {
+	charset := confusableChars(ConfusableMask(ConfusableZero | ConfusableRNM | ConfusableQuotes))
+	if charset.String() != "0Oom'\"`" {
+		t.Errorf("confusableChars() failed, expected: %s, got: %s", "0Oom'\"`", charset.String())
+	}
+}
+
+func TestContainsConfusableSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		mask     ConfusableMask
+		want     bool
+	}{
+		{"rn sequence", "abcrnxyz", ConfusableMask(ConfusableRNM), true},
+		{"vv sequence", "abcVVxyz", ConfusableMask(ConfusableVVW), true},
+		{"No sequence", "abcrxnyz", ConfusableMask(ConfusableRNM | ConfusableVVW), false},
+		{"Group not set", "abcrnxyz", ConfusableMask(ConfusableVVW), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsConfusableSequence(tt.password, tt.mask); got != tt.want {
+				t.Errorf("containsConfusableSequence() failed, expected: %t, got: %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateConfusables(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithConfusables(ConfusablePresetHandwriting),
+		WithModeMask(ModeLowerCase|ModeNumeric|ModeSpecial|ModeUpperCase))
+	generator := New(config)
+	for range 100 {
+		password, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if strings.ContainsAny(password, "0Oo1lI|5S2Z8Bmw\"'`W") {
+			t.Errorf("Generate() failed, password %q contains confusable characters", password)
+		}
+		if strings.Contains(password, "rn") || strings.Contains(password, "vv") ||
+			strings.Contains(password, "VV") {
+			t.Errorf("Generate() failed, password %q contains confusable sequences", password)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// sequenceRanges is a list of character sequences that are considered sequential
+// runs when used in a password. This includes the alphabet, the digits and the
+// rows of a US QWERTY keyboard. Each sequence is checked in ascending and descending
+// order and the check is case-insensitive.
+var sequenceRanges = []string{
+	CharRangeAlphaLower,
+	"0123456789",
+	"1234567890",
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+	"~!@#$%^&*()_+",
+	"qwertyuiop{}|",
+	`asdfghjkl:"`,
+	"zxcvbnm<>?",
+}
+
+// checkConstraints checks if a password meets the sequence, repetition and position
+// constraints, the character mode amounts, the forbidden words, the confusable character
+// sequences and the imported password policies, that are specified in the generator's
+// configuration. It returns true if the password meets the constraints, otherwise it
+// returns false.
+func (g *Generator) checkConstraints(password string) bool {
+	return g.checkCharConstraints([]rune(password)) && g.checkPasswordPolicies(password)
+}
+
+// checkCheckCharConstraints checks if a password with check characters (see
+// AddCheckChars) meets the constraints of the generator's configuration. The
+// check characters are part of the character constraints, the separators between
+// the groups are not. The forbidden words, confusable sequences and imported
+// password policies are checked against the complete password
+func (g *Generator) checkCheckCharConstraints(password string) bool {
+	chars := withoutCheckCharSeparators([]rune(password), g.config.CheckCharGroupSize)
+	return g.checkMinimumRequirements(string(chars)) && g.checkCharConstraints(chars) &&
+		g.checkPasswordPolicies(password)
+}
+
+// checkCharConstraints checks if the given characters meet the sequence, repetition,
+// position and character mode constraints of the generator's configuration
+func (g *Generator) checkCharConstraints(chars []rune) bool {
+	if len(chars) == 0 {
+		return true
+	}
+	if g.config.FirstCharMode > 0 && !MaskHasMode(g.config.FirstCharMode, g.modeOfChar(chars[0])) {
+		return false
+	}
+	if g.config.LastCharMode > 0 && !MaskHasMode(g.config.LastCharMode, g.modeOfChar(chars[len(chars)-1])) {
+		return false
+	}
+	// Repetitions and uniqueness are checked per user-perceived character, as the
+	// password is generated from grapheme clusters
+	clusters := graphemeClusters(string(chars))
+	if g.config.MaxRepeat > 0 && longestRepeat(clusters) > g.config.MaxRepeat {
+		return false
+	}
+	if g.config.MaxClassRepeat > 0 && longestClassRepeat(chars, g.modeOfChar) > g.config.MaxClassRepeat {
+		return false
+	}
+	if g.config.MaxSequence > 0 && longestSequence(chars) > g.config.MaxSequence {
+		return false
+	}
+	if g.config.UniqueChars && !uniqueChars(clusters) {
+		return false
+	}
+	if !g.checkMinClasses(chars) {
+		return false
+	}
+	modeCount := g.countModes(chars)
+	for _, mode := range charModes {
+		_, maxAmount := g.amountsForMode(mode)
+		if maxAmount > 0 && modeCount[mode] > maxAmount {
+			return false
+		}
+	}
+	return true
+}
+
+// checkMinClasses checks if the given characters contain at least the minimum
+// amount of different character modes of the generator's configuration
+func (g *Generator) checkMinClasses(chars []rune) bool {
+	return g.config.MinClasses <= 0 || int64(len(g.countModes(chars))) >= g.config.MinClasses
+}
+
+// countModes returns the amount of characters per character Mode in the given
+// characters
+func (g *Generator) countModes(chars []rune) map[Mode]int64 {
+	modeCount := make(map[Mode]int64)
+	for _, char := range chars {
+		modeCount[g.modeOfChar(char)]++
+	}
+	return modeCount
+}
+
+// checkPasswordPolicies checks if a password does not contain forbidden words and
+// confusable character sequences and passes the imported password policies of the
+// generator's configuration
+func (g *Generator) checkPasswordPolicies(password string) bool {
+	if containsForbiddenWord(password, g.config.ForbiddenWords) {
+		return false
+	}
+	if g.config.Confusables > 0 && containsConfusableSequence(password, g.config.Confusables) {
+		return false
+	}
+	if g.config.PWQuality != nil && g.config.PWQuality.Check(password) != nil {
+		return false
+	}
+	if g.config.PasswordRules != nil && g.config.PasswordRules.Check(password) != nil {
+		return false
+	}
+	return true
+}
+
+// validateConstraints checks if the constraints specified in the generator's
+// configuration can be satisfied by a password of the given length that is
+// constructed from the given range of characters. If the constraints can never
+// be met, ErrUnsatisfiableConstraints is returned. If a custom character range
+// or character class is not valid, an error wrapping ErrInvalidCharRange is
+// returned.
+func (g *Generator) validateConstraints(length int64, charRange string) error {
+	if _, err := KeyboardSafeCharset(g.config.KeyboardLayouts...); err != nil {
+		return err
+	}
+	for mode, customRange := range g.config.CharRanges {
+		if !MaskHasMode(g.config.Mode, mode) {
+			continue
+		}
+		if err := validateCharRange(customRange); err != nil {
+			return fmt.Errorf("invalid custom character range: %w", err)
+		}
+	}
+	clusters := graphemeClusters(norm.NFC.String(charRange))
+	for _, class := range g.config.CharClasses {
+		if err := validateCharRange(class.Chars); err != nil {
+			return fmt.Errorf("invalid character class %q: %w", class.Name, err)
+		}
+		if class.MinAmount <= 0 {
+			continue
+		}
+		var available int64
+		for _, cluster := range graphemeClusters(NewCharset(class.Chars).String()) {
+			if slices.Contains(clusters, cluster) {
+				available++
+			}
+		}
+		if available == 0 || class.MinAmount > length || (g.config.UniqueChars && class.MinAmount > available) {
+			return ErrUnsatisfiableConstraints
+		}
+	}
+
+	// Check characters are part of the password, but are not random
+	total := length
+	if g.config.CheckCharGroupSize > 0 {
+		if g.isExcludedFunc()([]rune(CheckCharSeparator)[0]) {
+			return fmt.Errorf("%w: the check character separator %q is excluded",
+				ErrUnsatisfiableConstraints, CheckCharSeparator)
+		}
+		total += (length + g.config.CheckCharGroupSize - 1) / g.config.CheckCharGroupSize
+	}
+
+	// Uniqueness and repetitions are checked per grapheme cluster, the character
+	// modes per rune
+	distinct := make(map[string]struct{})
+	for _, cluster := range clusters {
+		distinct[cluster] = struct{}{}
+	}
+	modeCount := make(map[Mode]int64)
+	for _, char := range NewCharset(charRange).Runes() {
+		modeCount[g.modeOfChar(char)]++
+	}
+	if g.config.UniqueChars && total > int64(len(distinct)) {
+		return ErrUnsatisfiableConstraints
+	}
+	if g.config.MaxRepeat > 0 && len(distinct) == 1 && total > g.config.MaxRepeat {
+		return ErrUnsatisfiableConstraints
+	}
+	if g.config.MaxClassRepeat > 0 && len(modeCount) == 1 && total > g.config.MaxClassRepeat {
+		return ErrUnsatisfiableConstraints
+	}
+	if g.config.MinClasses > min(int64(len(modeCount)), length) {
+		return ErrUnsatisfiableConstraints
+	}
+
+	var minSum, maxSum int64
+	unbounded := false
+	for _, mode := range charModes {
+		minAmount, maxAmount := g.amountsForMode(mode)
+		if maxAmount > 0 && minAmount > maxAmount {
+			return ErrUnsatisfiableConstraints
+		}
+		if minAmount > modeCount[mode]*length ||
+			(g.config.UniqueChars && minAmount > modeCount[mode]) {
+			return ErrUnsatisfiableConstraints
+		}
+		minSum += minAmount
+		if modeCount[mode] == 0 {
+			continue
+		}
+		if maxAmount <= 0 {
+			unbounded = true
+			continue
+		}
+		maxSum += maxAmount
+	}
+	if modeCount[0] > 0 {
+		unbounded = true
+	}
+	if minSum > length || (!unbounded && maxSum < total) {
+		return ErrUnsatisfiableConstraints
+	}
+
+	for _, mask := range []ModeMask{g.config.FirstCharMode, g.config.LastCharMode} {
+		if mask == 0 {
+			continue
+		}
+		found := false
+		for mode, count := range modeCount {
+			if count > 0 && MaskHasMode(mask, mode) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrUnsatisfiableConstraints
+		}
+	}
+	return nil
+}
+
+// amountsForMode returns the configured minimum and maximum amount of characters
+// for the given character Mode
+func (g *Generator) amountsForMode(mode Mode) (int64, int64) {
+	switch mode {
+	case ModeLowerCase:
+		return g.config.MinLowerCase, g.config.MaxLowerCase
+	case ModeNumeric:
+		return g.config.MinNumeric, g.config.MaxNumeric
+	case ModeSpecial:
+		return g.config.MinSpecial, g.config.MaxSpecial
+	case ModeUpperCase:
+		return g.config.MinUpperCase, g.config.MaxUpperCase
+	default:
+		return 0, 0
+	}
+}
+
+// modeOfChar returns the character Mode the given character belongs to. If the
+// character is not part of any character mode, 0 is returned
+func (g *Generator) modeOfChar(char rune) Mode {
+	for _, mode := range charModes {
+		if strings.ContainsRune(g.charRangeForMode(mode), char) {
+			return mode
+		}
+	}
+	return 0
+}
+
+// containsForbiddenWord returns true if the given password contains any of the given
+// words. The check is case-insensitive
+func containsForbiddenWord(password string, words []string) bool {
+	lower := strings.ToLower(password)
+	for _, word := range words {
+		if word != "" && strings.Contains(lower, strings.ToLower(word)) {
+			return true
+		}
+	}
+	return false
+}
+
+// longestRepeat returns the length of the longest run of identical consecutive
+// characters (runes or grapheme clusters) in the given slice of characters
+func longestRepeat[T comparable](chars []T) int64 {
+	var longest, current int64
+	for i := range chars {
+		if i > 0 && chars[i] == chars[i-1] {
+			current++
+		} else {
+			current = 1
+		}
+		if current > longest {
+			longest = current
+		}
+	}
+	return longest
+}
+
+// longestClassRepeat returns the length of the longest run of consecutive characters
+// of the same character class in the given slice of characters. The class of a
+// character is determined by the given classify function
+func longestClassRepeat(chars []rune, classify func(rune) Mode) int64 {
+	var longest, current int64
+	for i := range chars {
+		if i > 0 && classify(chars[i]) == classify(chars[i-1]) {
+			current++
+		} else {
+			current = 1
+		}
+		if current > longest {
+			longest = current
+		}
+	}
+	return longest
+}
+
+// longestSequence returns the length of the longest sequential run of characters
+// in the given slice of characters, based on the sequences in sequenceRanges
+func longestSequence(chars []rune) int64 {
+	if len(chars) == 0 {
+		return 0
+	}
+	lower := make([]rune, len(chars))
+	for i, char := range chars {
+		lower[i] = unicode.ToLower(char)
+	}
+	var longest int64 = 1
+	for _, sequence := range sequenceRanges {
+		for _, direction := range []int{1, -1} {
+			var current int64 = 1
+			for i := 1; i < len(lower); i++ {
+				prev := strings.IndexRune(sequence, lower[i-1])
+				next := strings.IndexRune(sequence, lower[i])
+				if prev >= 0 && next >= 0 && next-prev == direction {
+					current++
+				} else {
+					current = 1
+				}
+				if current > longest {
+					longest = current
+				}
+			}
+		}
+	}
+	return longest
+}
+
+// uniqueChars returns true if every character (rune or grapheme cluster) in the
+// given slice of characters is only present once
+func uniqueChars[T comparable](chars []T) bool {
+	seen := make(map[T]struct{}, len(chars))
+	for _, char := range chars {
+		if _, ok := seen[char]; ok {
+			return false
+		}
+		seen[char] = struct{}{}
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerator_checkConstraints(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		password string
+		want     bool
+	}{
+		{"No constraints", nil, "aaabbbccc", true},
+		{"Empty password", []Option{WithMaxRepeat(1)}, "", true},
+		{"MaxRepeat met", []Option{WithMaxRepeat(2)}, "aabbaacc", true},
+		{"MaxRepeat exceeded", []Option{WithMaxRepeat(2)}, "aabbbacc", false},
+		{"MaxSequence met", []Option{WithMaxSequence(2)}, "ab9x", true},
+		{"MaxSequence ascending", []Option{WithMaxSequence(2)}, "xabc9", false},
+		{"MaxSequence descending", []Option{WithMaxSequence(2)}, "x3219", false},
+		{"MaxSequence case-insensitive", []Option{WithMaxSequence(2)}, "xAbC9", false},
+		{"MaxSequence keyboard walk", []Option{WithMaxSequence(2)}, "1qwe9", false},
+		{"MaxSequence digit wrap", []Option{WithMaxSequence(2)}, "a890b", false},
+		{"FirstCharMode met", []Option{WithFirstCharMode(ModeLowerCase | ModeUpperCase)}, "Ab12", true},
+		{"FirstCharMode failed", []Option{WithFirstCharMode(ModeLowerCase | ModeUpperCase)}, "1bcd", false},
+		{"LastCharMode met", []Option{WithLastCharMode(ModeNumeric)}, "abc1", true},
+		{"LastCharMode failed", []Option{WithLastCharMode(ModeNumeric)}, "abc1d", false},
+		{"UniqueChars met", []Option{WithUniqueChars()}, "abcABC", true},
+		{"UniqueChars failed", []Option{WithUniqueChars()}, "abca", false},
+		{"MaxLowercase met", []Option{WithMaxLowercase(2)}, "abCD", true},
+		{"MaxLowercase exceeded", []Option{WithMaxLowercase(2)}, "abcD", false},
+		{"MaxNumeric exceeded", []Option{WithMaxNumeric(1)}, "a1b2", false},
+		{"MaxSpecial exceeded", []Option{WithModeMask(ModeSpecial | ModeLowerCase), WithMaxSpecial(1)}, "a!b#", false},
+		{"MaxUppercase exceeded", []Option{WithMaxUppercase(1)}, "AbC", false},
+		{"MinClasses met", []Option{WithMinClasses(3)}, "aB3", true},
+		{"MinClasses failed", []Option{WithMinClasses(3)}, "aBc", false},
+		{"MaxClassRepeat met", []Option{WithMaxClassRepeat(2)}, "abC1d", true},
+		{"MaxClassRepeat exceeded", []Option{WithMaxClassRepeat(2)}, "abcD1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(NewConfig(tt.opts...))
+			if got := g.checkConstraints(tt.password); got != tt.want {
+				t.Errorf("checkConstraints(%q) failed, expected: %t, got: %t", tt.password, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerator_validateConstraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		length  int64
+		wantErr bool
+	}{
+		{"No constraints", nil, 20, false},
+		{"Unique with enough chars", []Option{WithModeMask(ModeNumeric), WithUniqueChars()}, 10, false},
+		{"Unique with too few chars", []Option{WithModeMask(ModeNumeric), WithUniqueChars()}, 11, true},
+		{"Min greater than max", []Option{WithMinNumeric(3), WithMaxNumeric(2)}, 10, true},
+		{"Minimum sum exceeds length", []Option{WithMinNumeric(3), WithMinLowercase(3)}, 5, true},
+		{"Maximum sum below length", []Option{
+			WithModeMask(ModeNumeric | ModeLowerCase),
+			WithMaxNumeric(2), WithMaxLowercase(2),
+		}, 5, true},
+		{"Maximum sum with unbounded mode", []Option{WithMaxNumeric(2), WithMaxLowercase(2)}, 5, false},
+		{"Single char with repeat limit", []Option{
+			WithModeMask(ModeNumeric), WithExcludeChars("123456789"),
+			WithMaxRepeat(2),
+		}, 3, true},
+		{"FirstCharMode not available", []Option{
+			WithModeMask(ModeNumeric),
+			WithFirstCharMode(ModeLowerCase),
+		}, 10, true},
+		{"LastCharMode available", []Option{WithLastCharMode(ModeNumeric)}, 10, false},
+		{"MinClasses not available", []Option{WithModeMask(ModeNumeric | ModeLowerCase), WithMinClasses(3)}, 10, true},
+		{"MinClasses exceeds length", []Option{WithMinClasses(3)}, 2, true},
+		{"MaxClassRepeat with single mode", []Option{WithModeMask(ModeNumeric), WithMaxClassRepeat(2)}, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(NewConfig(tt.opts...))
+			err := g.validateConstraints(tt.length, g.GetCharRangeFromConfig())
+			if tt.wantErr && !errors.Is(err, ErrUnsatisfiableConstraints) {
+				t.Errorf("validateConstraints() expected ErrUnsatisfiableConstraints, got: %s", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateConstraints() failed: %s", err)
+			}
+		})
+	}
+}
+
+func TestGenerateRandom_withConstraints(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeNumeric|
+		ModeSpecial|ModeUpperCase), WithFixedLength(20), WithMaxRepeat(1), WithMaxSequence(2),
+		WithFirstCharMode(ModeLowerCase|ModeUpperCase), WithLastCharMode(ModeNumeric),
+		WithUniqueChars(), WithMaxSpecial(2))
+	g := New(config)
+	for range 100 {
+		pw, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if !g.checkConstraints(pw) {
+			t.Errorf("Generate() returned password that does not meet the constraints: %s", pw)
+		}
+	}
+}
+
+func TestGenerateRandom_unsatisfiable(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric),
+		WithFixedLength(11), WithUniqueChars())
+	g := New(config)
+	if _, err := g.Generate(); !errors.Is(err, ErrUnsatisfiableConstraints) {
+		t.Errorf("Generate() expected ErrUnsatisfiableConstraints, got: %s", err)
+	}
+}
+
+func TestGenerateRandom_uniqueGraphemeClusters(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int64
+		wantErr bool
+	}{
+		{"all flags", 4, false},
+		{"more flags than available", 5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The flags share regional indicator symbols, i. e. "🇩🇪" and "🇪🇸"
+			config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(0),
+				WithCharClass("flags", "🇩🇪🇫🇷🇮🇹🇪🇸", 0), WithFixedLength(tt.length), WithUniqueChars())
+			g := New(config)
+			pw, err := g.Generate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsatisfiableConstraints) {
+					t.Errorf("Generate() expected ErrUnsatisfiableConstraints, got: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() failed: %s", err)
+			}
+			if clusters := graphemeClusters(pw); !uniqueChars(clusters) || len(clusters) != 4 {
+				t.Errorf("Generate() returned password with duplicate characters: %s", pw)
+			}
+		})
+	}
+}
+
+func TestLongestSequence(t *testing.T) {
+	tests := []struct {
+		chars string
+		want  int64
+	}{
+		{"", 0},
+		{"a", 1},
+		{"ax9", 1},
+		{"abcd", 4},
+		{"dcba", 4},
+		{"qazwsx", 1},
+		{"asdfg123", 5},
+		{"!@#$", 4},
+		{"xyzabc", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.chars, func(t *testing.T) {
+			if got := longestSequence([]rune(tt.chars)); got != tt.want {
+				t.Errorf("longestSequence(%q) failed, expected: %d, got: %d", tt.chars, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLongestRepeat(t *testing.T) {
+	tests := []struct {
+		chars string
+		want  int64
+	}{
+		{"", 0},
+		{"a", 1},
+		{"aab", 2},
+		{"abbbba", 4},
+		{"aAa", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.chars, func(t *testing.T) {
+			if got := longestRepeat([]rune(tt.chars)); got != tt.want {
+				t.Errorf("longestRepeat(%q) failed, expected: %d, got: %d", tt.chars, tt.want, got)
+			}
+		})
+	}
+}
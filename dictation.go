@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CheckCharSeparator is the separator between the groups of a password with check
+// characters
+const CheckCharSeparator = "-"
+
+// ErrCheckCharMismatch is returned if the check character of a group does not match
+// the characters of the group
+var ErrCheckCharMismatch = errors.New("check character does not match")
+
+// AddCheckChars splits the given password into groups of the given size and appends a
+// check character to each group. The check characters are calculated with the Luhn
+// mod N algorithm over the given alphabet, which detects any single mistyped character
+// and most transpositions of adjacent characters. For alphabets with an odd number of
+// characters, the doubled code points are not reduced to their digit sum, which makes
+// the check detect all transpositions of adjacent characters as well. The groups are
+// joined with the CheckCharSeparator.
+func AddCheckChars(password, alphabet string, groupSize int64) (string, error) {
+	if groupSize <= 0 {
+		return "", ErrInvalidLength
+	}
+	chars := NewCharset(alphabet).Runes()
+	var groups []string
+	for _, group := range splitGroups([]rune(password), int(groupSize)) {
+		check, err := luhnModN(group, chars)
+		if err != nil {
+			return "", err
+		}
+		groups = append(groups, string(append(group, check)))
+	}
+	return strings.Join(groups, CheckCharSeparator), nil
+}
+
+// VerifyCheckChars verifies the check characters of the given password that has been
+// created by AddCheckChars with the given alphabet and group size. If a check character
+// does not match its group, an error wrapping ErrCheckCharMismatch is returned
+func VerifyCheckChars(password, alphabet string, groupSize int64) error {
+	if groupSize <= 0 {
+		return ErrInvalidLength
+	}
+	chars := NewCharset(alphabet).Runes()
+	runes := []rune(password)
+	number := 0
+	for len(runes) > 0 {
+		end := min(int(groupSize)+1, len(runes))
+		group := runes[:end]
+		runes = runes[end:]
+		number++
+		if len(runes) > 0 {
+			if string(runes[0]) != CheckCharSeparator {
+				return fmt.Errorf("%w: missing separator after group %d", ErrCheckCharMismatch, number)
+			}
+			runes = runes[1:]
+		}
+		if len(group) < 2 {
+			return fmt.Errorf("%w: group %d is too short", ErrCheckCharMismatch, number)
+		}
+		check, err := luhnModN(group[:len(group)-1], chars)
+		if err != nil {
+			return err
+		}
+		if check != group[len(group)-1] {
+			return fmt.Errorf("%w: group %d (%s)", ErrCheckCharMismatch, number, string(group))
+		}
+	}
+	return nil
+}
+
+// VerifyCheckChars verifies the check characters of the given password, based on the
+// character range and the check character group size of the generator's configuration.
+// See VerifyCheckChars for details
+func (g *Generator) VerifyCheckChars(password string) error {
+	return VerifyCheckChars(password, g.GetCharRangeFromConfig(), g.config.CheckCharGroupSize)
+}
+
+// luhnModN calculates the Luhn mod N check character of the given characters based
+// on the given alphabet. See AddCheckChars for details. If a character is not part
+// of the alphabet, an error wrapping ErrInvalidCharRange is returned
+func luhnModN(group, alphabet []rune) (rune, error) {
+	base := len(alphabet)
+	if base == 0 {
+		return 0, ErrInvalidCharRange
+	}
+	index := make(map[rune]int, base)
+	for i, char := range alphabet {
+		index[char] = i
+	}
+	factor, sum := 2, 0
+	for i := len(group) - 1; i >= 0; i-- {
+		codePoint, ok := index[group[i]]
+		if !ok {
+			return 0, fmt.Errorf("%w: character %q is not part of the alphabet", ErrInvalidCharRange, group[i])
+		}
+		addend := factor * codePoint
+		if factor == 2 {
+			factor = 1
+		} else {
+			factor = 2
+		}
+		// Luhn mod N relies on the digit sum of the doubled code point, which only
+		// detects all single errors for an even base. For an odd base, doubling is a
+		// permutation modulo the base by itself
+		if base%2 == 0 {
+			addend = addend/base + addend%base
+		}
+		sum += addend
+	}
+	return alphabet[(base-sum%base)%base], nil
+}
+
+// checkCharsLength returns the length of a password of the given length after the
+// check characters and separators for the given group size have been added by
+// AddCheckChars
+func checkCharsLength(length, groupSize int64) int64 {
+	if length <= 0 {
+		return 0
+	}
+	groups := (length + groupSize - 1) / groupSize
+	return length + groups + groups - 1
+}
+
+// checkCharPayloadLengths returns all lengths of the random part of a password, for
+// which the length of the password with check characters and separators is between
+// the given minimum and maximum length
+func checkCharPayloadLengths(minLength, maxLength, groupSize int64) []int64 {
+	var lengths []int64
+	for length := int64(1); checkCharsLength(length, groupSize) <= maxLength; length++ {
+		if checkCharsLength(length, groupSize) >= minLength {
+			lengths = append(lengths, length)
+		}
+	}
+	return lengths
+}
+
+// withoutCheckCharSeparators returns the characters of a password with check
+// characters of the given group size without the separators between the groups
+func withoutCheckCharSeparators(chars []rune, groupSize int64) []rune {
+	stripped := make([]rune, 0, len(chars))
+	for i, char := range chars {
+		// Every group of groupSize characters is followed by its check character
+		// and the separator
+		if int64(i+1)%(groupSize+2) == 0 {
+			continue
+		}
+		stripped = append(stripped, char)
+	}
+	return stripped
+}
+
+// splitGroups splits the given characters into groups of the given size. The last
+// group might be shorter
+func splitGroups(chars []rune, size int) [][]rune {
+	var groups [][]rune
+	for len(chars) > 0 {
+		end := min(size, len(chars))
+		groups = append(groups, chars[:end:end])
+		chars = chars[end:]
+	}
+	return groups
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAddCheckChars(t *testing.T) {
+	tests := []struct {
+		name      string
+		password  string
+		alphabet  string
+		groupSize int64
+		want      string
+	}{
+		// Luhn mod 10 equals the Luhn algorithm for numbers
+		{"Luhn digits", "7992739871", CharRangeNumeric[9:] + CharRangeNumeric[:9], 10, "79927398713"},
+		{"Groups", "12345678", "0123456789", 4, "12344-56788"},
+		{"Short last group", "123456", "0123456789", 4, "12344-562"},
+		{"Hex alphabet", "1a2b", "0123456789abcdef", 2, "1aa-2b7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := AddCheckChars(tt.password, tt.alphabet, tt.groupSize)
+			if err != nil {
+				t.Fatalf("AddCheckChars() failed: %s", err)
+			}
+			if result != tt.want {
+				t.Errorf("AddCheckChars() failed, expected: %s, got: %s", tt.want, result)
+			}
+			if err = VerifyCheckChars(result, tt.alphabet, tt.groupSize); err != nil {
+				t.Errorf("VerifyCheckChars() failed: %s", err)
+			}
+		})
+	}
+}
+
+func TestAddCheckChars_fail(t *testing.T) {
+	if _, err := AddCheckChars("abc", "abc", 0); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("AddCheckChars() failed, expected error: %s, got: %s", ErrInvalidLength, err)
+	}
+	if _, err := AddCheckChars("abcd", "abc", 2); !errors.Is(err, ErrInvalidCharRange) {
+		t.Errorf("AddCheckChars() failed, expected error: %s, got: %s", ErrInvalidCharRange, err)
+	}
+}
+
+func TestVerifyCheckChars_errors(t *testing.T) {
+	alphabet := CharRangeAlphaLowerDictation + CharRangeNumeric
+	secret, err := AddCheckChars("ahk7qz0rlu", alphabet, 5)
+	if err != nil {
+		t.Fatalf("AddCheckChars() failed: %s", err)
+	}
+
+	// Every single mistyped character has to be detected
+	chars := []rune(secret)
+	for position, original := range chars {
+		if string(original) == CheckCharSeparator {
+			continue
+		}
+		for _, replacement := range alphabet {
+			if replacement == original {
+				continue
+			}
+			chars[position] = replacement
+			if err = VerifyCheckChars(string(chars), alphabet, 5); !errors.Is(err, ErrCheckCharMismatch) {
+				t.Errorf("VerifyCheckChars() failed, expected error for %s, got: %s", string(chars), err)
+			}
+		}
+		chars[position] = original
+	}
+
+	// Every transposition of adjacent characters within a group has to be detected
+	for position := 0; position < len(chars)-1; position++ {
+		if chars[position] == chars[position+1] || string(chars[position]) == CheckCharSeparator ||
+			string(chars[position+1]) == CheckCharSeparator {
+			continue
+		}
+		chars[position], chars[position+1] = chars[position+1], chars[position]
+		if err = VerifyCheckChars(string(chars), alphabet, 5); !errors.Is(err, ErrCheckCharMismatch) {
+			t.Errorf("VerifyCheckChars() failed, expected error for %s, got: %s", string(chars), err)
+		}
+		chars[position], chars[position+1] = chars[position+1], chars[position]
+	}
+
+	if err = VerifyCheckChars(strings.ReplaceAll(secret, CheckCharSeparator, ""), alphabet, 5); err == nil {
+		t.Errorf("VerifyCheckChars() failed, expected error for missing separator")
+	}
+	if err = VerifyCheckChars(secret+CheckCharSeparator+"a", alphabet, 5); err == nil {
+		t.Errorf("VerifyCheckChars() failed, expected error for too short group")
+	}
+}
+
+func TestGenerator_GenerateDictation(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeUpperCase|ModeNumeric|
+		ModeDictation), WithCheckChars(4))
+	generator := New(config)
+	for range 100 {
+		password, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if strings.ContainsAny(password, "bdptvegmnfsABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			t.Errorf("Generate() failed, password %q contains characters that are not dictation-safe",
+				password)
+		}
+		if err = generator.VerifyCheckChars(password); err != nil {
+			t.Errorf("VerifyCheckChars() failed for password %q: %s", password, err)
+		}
+		if _, err = Spell(password); err != nil {
+			t.Errorf("Spell() failed for password %q: %s", password, err)
+		}
+	}
+}
+
+func TestGenerator_GenerateCheckCharsLength(t *testing.T) {
+	rules, err := ParsePasswordRules("maxlength: 8")
+	if err != nil {
+		t.Fatalf("ParsePasswordRules() failed: %s", err)
+	}
+	tests := []struct {
+		name      string
+		options   []Option
+		minLength int
+		maxLength int
+	}{
+		{"fixed length 8", []Option{WithFixedLength(8)}, 8, 8},
+		{"fixed length 11", []Option{WithFixedLength(11)}, 11, 11},
+		{"length 9 to 14", []Option{WithMinLength(9), WithMaxLength(14)}, 9, 14},
+		{
+			"password rules maxlength 8", []Option{WithFixedLength(8), WithPasswordRules(rules)},
+			8, 8,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options := append([]Option{
+				WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase | ModeDictation),
+				WithCheckChars(4),
+			}, tc.options...)
+			generator := New(NewConfig(options...))
+			for range 100 {
+				password, err := generator.Generate()
+				if err != nil {
+					t.Fatalf("Generate() failed: %s", err)
+				}
+				if len(password) < tc.minLength || len(password) > tc.maxLength {
+					t.Errorf("Generate() failed, expected length between %d and %d, got: %d (%q)",
+						tc.minLength, tc.maxLength, len(password), password)
+				}
+				if err = generator.VerifyCheckChars(password); err != nil {
+					t.Errorf("VerifyCheckChars() failed for password %q: %s", password, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateCheckCharsConstraints(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeNumeric),
+		WithCheckChars(3), WithFixedLength(14), WithMaxRepeat(1), WithMaxSequence(2))
+	generator := New(config)
+	for range 100 {
+		password, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if !generator.checkCheckCharConstraints(password) {
+			t.Errorf("Generate() failed, password %q does not meet the constraints", password)
+		}
+	}
+}
+
+func TestGenerator_GenerateCheckChars_fail(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{"unreachable length", []Option{WithFixedLength(6)}},
+		{"excluded separator", []Option{WithFixedLength(8), WithExcludeChars("-")}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options := append([]Option{
+				WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase), WithCheckChars(4),
+			}, tc.options...)
+			generator := New(NewConfig(options...))
+			if _, err := generator.Generate(); !errors.Is(err, ErrUnsatisfiableConstraints) {
+				t.Errorf("Generate() failed, expected: %s, got: %v", ErrUnsatisfiableConstraints, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,578 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Entropy returns the estimated entropy (in bits) of a single password that is
+// generated by the Generator with its current Config.
+//
+// For AlgoRandom the estimation takes the configured length range, the character
+// range and all configured constraints (minimum and maximum amounts per character
+// mode and custom character class, minimum amount of character modes, repetition,
+// sequence, position and uniqueness constraints) into account. If character class
+// weights are configured, the entropy is based on the resulting non-uniform
+// distribution of the characters.
+// Constraints are treated as independent from each other, which makes the result
+// an estimation rather than an exact value. For AlgoPronounceable the entropy is
+// estimated based on the configured LengthStrategy and the average syllable length
+// of the syllable pool. The capitalization style and the leetspeak substitution add
+// to the entropy as far as they are random; separators do not add any entropy. The
+// estimation assumes that every password has a unique split into the syllables of
+// the pool, which is not guaranteed by SyllableSet.Validate (i. e. "ka" and "no"
+// vs. "kan" and "o"). For sets with ambiguous splits the result is an upper bound.
+func (g *Generator) Entropy() (float64, error) {
+	switch g.config.Algorithm {
+	case AlgoPronounceable:
+		return g.entropyPronounceable()
+	case AlgoRandom:
+		return g.entropyRandom()
+	case AlgoCoinFlip:
+		return 1, nil
+	case AlgoBinary:
+		length := DefaultBinarySize
+		if g.config.FixedLength > 0 {
+			length = g.config.FixedLength
+		}
+		return float64(length * 8), nil
+	default:
+		return 0, fmt.Errorf("unsupported algorithm")
+	}
+}
+
+// passwordLengths returns the list of all possible password lengths based on the
+// given config parameters. For random passwords with check characters, the lengths
+// of the random part of the password are returned
+func (g *Generator) passwordLengths() []int64 {
+	minLength := g.config.MinLength
+	maxLength := g.config.MaxLength
+	if g.config.FixedLength > 0 {
+		minLength, maxLength = g.config.FixedLength, g.config.FixedLength
+	}
+	if minLength > maxLength {
+		maxLength = minLength
+	}
+	if g.config.Algorithm == AlgoRandom && g.config.CheckCharGroupSize > 0 {
+		return checkCharPayloadLengths(minLength, maxLength, g.config.CheckCharGroupSize)
+	}
+	if g.config.FixedLength > 0 {
+		return []int64{g.config.FixedLength}
+	}
+	lengths := make([]int64, 0, maxLength-minLength+1)
+	for length := minLength; length <= maxLength; length++ {
+		if length <= 0 {
+			lengths = append(lengths, 1)
+			continue
+		}
+		lengths = append(lengths, length)
+	}
+	return lengths
+}
+
+// entropyRandom estimates the entropy of a password generated with AlgoRandom
+func (g *Generator) entropyRandom() (float64, error) {
+	// The password is constructed from user-perceived characters (grapheme clusters),
+	// which can consist of multiple code points
+	allChars := graphemeClusters(g.GetCharRangeFromConfig())
+	if len(allChars) == 0 {
+		return 0, ErrInvalidCharRange
+	}
+	allProbabilities, err := g.charProbabilities(allChars)
+	if err != nil {
+		return 0, err
+	}
+
+	// Characters that are never selected do not contribute to the entropy
+	var chars []string
+	var probabilities []float64
+	var charBits float64
+	for i, probability := range allProbabilities {
+		if probability <= 0 {
+			continue
+		}
+		chars = append(chars, allChars[i])
+		probabilities = append(probabilities, probability)
+		charBits -= probability * math.Log2(probability)
+	}
+	charRange := strings.Join(chars, "")
+
+	lengths := g.passwordLengths()
+	if len(lengths) == 0 {
+		return 0, ErrUnsatisfiableConstraints
+	}
+	structure := g.probabilityStructure(chars, probabilities, int(slices.Max(lengths)))
+	var sum float64
+	for _, length := range lengths {
+		if err = g.validateConstraints(length, charRange); err != nil {
+			return 0, err
+		}
+		lengthBits := float64(length) * charBits
+		for _, probability := range []float64{
+			structure[length],
+			g.probabilityModeAmounts(chars, probabilities, int(length)),
+			g.probabilityClassAmounts(chars, probabilities, int(length)),
+			g.probabilityMinClasses(chars, probabilities, int(length)),
+			g.probabilityUnique(chars, probabilities, int(length)),
+		} {
+			if probability <= 0 {
+				return 0, ErrUnsatisfiableConstraints
+			}
+			lengthBits += math.Log2(probability)
+		}
+		sum += lengthBits
+	}
+	return math.Log2(float64(len(lengths))) + sum/float64(len(lengths)), nil
+}
+
+// entropyPronounceable estimates the entropy of a password generated with
+// AlgoPronounceable
+func (g *Generator) entropyPronounceable() (float64, error) {
+	if err := g.syllableSet().Validate(); err != nil {
+		return 0, err
+	}
+	pool := g.pronounceablePool()
+	if len(pool) == 0 {
+		return 0, ErrUnsatisfiableConstraints
+	}
+	var totalLength, capitalBits, leetspeakBits, capitalizable float64
+	for _, item := range pool {
+		totalLength += float64(utf8.RuneCountInString(item))
+		if positions := len(g.capitalizablePositions(item)); positions > 0 {
+			capitalizable++
+			if g.config.Capitalization == CapitalizationRandomLetter {
+				// A coinflip decides if one of the characters of the syllable
+				// is turned into upper-case
+				capitalBits += 1 + 0.5*math.Log2(float64(positions))
+			}
+		}
+		if g.config.Leetspeak {
+			// A coinflip decides for each character if it is substituted
+			leetspeakBits += float64(len(g.leetspeakPositions(item)))
+		}
+	}
+	separatorLength := float64(g.separatorLength())
+	averageLength := totalLength / float64(len(pool))
+	itemBits := math.Log2(float64(len(pool))) + (capitalBits+leetspeakBits)/float64(len(pool))
+
+	lengths := g.passwordLengths()
+	var sum float64
+	for _, length := range lengths {
+		var items float64
+		switch g.config.LengthStrategy {
+		case LengthStrategyExact:
+			// A combination of k syllables has a probability of the weight of the
+			// combination divided by the total weight of all combinations
+			totalLength := length + g.separatorLength()
+			weights := exactLengthWeights(totalLength, pool, g.separatorLength())
+			if weights[totalLength].Sign() == 0 {
+				return 0, ErrUnsatisfiableConstraints
+			}
+			items = exactLengthItems(totalLength, pool, g.separatorLength(), weights)
+			sum += log2BigInt(weights[totalLength]) - float64(totalLength)*math.Log2(float64(len(pool)))
+		case LengthStrategySyllables:
+			items = float64(length)
+		default:
+			items = math.Ceil((float64(length) + separatorLength) / (averageLength + separatorLength))
+		}
+		sum += items * itemBits
+		if g.config.Capitalization == CapitalizationRandomSyllable {
+			// One of the capitalizable syllables is selected for the conversion
+			sum += math.Log2(max(items*capitalizable/float64(len(pool)), 1))
+		}
+	}
+	return math.Log2(float64(len(lengths))) + sum/float64(len(lengths)), nil
+}
+
+// probabilityStructure returns, for every length from 0 up to the given maximum
+// length, the probability that a random string of that length, constructed from the
+// given characters with the given probabilities, meets the repetition, sequence and
+// position constraints of the generator's configuration. Sequences are treated as
+// a relation between two adjacent characters, which makes the result slightly
+// conservative compared to the actual check. The probabilities of all lengths are
+// calculated in a single pass, so that length ranges do not multiply the effort.
+func (g *Generator) probabilityStructure(chars []string, probabilities []float64, maxLength int) []float64 {
+	result := make([]float64, maxLength+1)
+	trackRepeat := g.config.MaxRepeat > 0 && !g.config.UniqueChars
+	trackClassRepeat := g.config.MaxClassRepeat > 0
+	trackSequence := g.config.MaxSequence > 0
+	if !trackRepeat && !trackClassRepeat && !trackSequence && g.config.FirstCharMode == 0 &&
+		g.config.LastCharMode == 0 {
+		for length := range result {
+			result[length] = 1
+		}
+		return result
+	}
+
+	// The state consists of the last character, the current repeat run length,
+	// the current class repeat run length, the current sequence run length and
+	// the direction of the sequence run (0: none, 1: ascending, 2: descending)
+	maxRepeat, maxClassRepeat, maxSequence := 1, 1, 1
+	if trackRepeat {
+		maxRepeat = int(min(g.config.MaxRepeat, int64(maxLength)))
+	}
+	if trackClassRepeat {
+		maxClassRepeat = int(min(g.config.MaxClassRepeat, int64(maxLength)))
+	}
+	if trackSequence {
+		maxSequence = int(min(g.config.MaxSequence, int64(maxLength)))
+	}
+	index := func(char, repeat, classRepeat, sequence, direction int) int {
+		return (((char*maxRepeat+repeat-1)*maxClassRepeat+classRepeat-1)*maxSequence+sequence-1)*3 + direction
+	}
+
+	numChars := len(chars)
+	follows := sequenceRelation(chars)
+	modes := make([]Mode, numChars)
+	allowedFirst := make([]bool, numChars)
+	allowedLast := make([]bool, numChars)
+	for i, char := range chars {
+		modes[i] = g.modeOfChar([]rune(char)[0])
+		allowedFirst[i] = g.config.FirstCharMode == 0 || MaskHasMode(g.config.FirstCharMode, modes[i])
+		allowedLast[i] = g.config.LastCharMode == 0 || MaskHasMode(g.config.LastCharMode, modes[i])
+	}
+
+	// The last character constraint only applies to the final position, so the
+	// probability of a length is the sum of the states that end with an allowed
+	// last character
+	stateSize := maxRepeat * maxClassRepeat * maxSequence * 3
+	total := func(states []float64) float64 {
+		var sum float64
+		for state, probability := range states {
+			if allowedLast[state/stateSize] {
+				sum += probability
+			}
+		}
+		return sum
+	}
+
+	result[0] = 1
+	if maxLength == 0 {
+		return result
+	}
+	current := make([]float64, numChars*stateSize)
+	for i := range chars {
+		if allowedFirst[i] {
+			current[index(i, 1, 1, 1, 0)] = probabilities[i]
+		}
+	}
+	result[1] = total(current)
+
+	for position := 1; position < maxLength; position++ {
+		next := make([]float64, len(current))
+		for state, probability := range current {
+			if probability == 0 {
+				continue
+			}
+			direction := state % 3
+			rest := state / 3
+			sequence := rest%maxSequence + 1
+			rest /= maxSequence
+			classRepeat := rest%maxClassRepeat + 1
+			rest /= maxClassRepeat
+			repeat := rest%maxRepeat + 1
+			char := rest / maxRepeat
+			for nextChar := 0; nextChar < numChars; nextChar++ {
+				nextRepeat := 1
+				if trackRepeat && nextChar == char {
+					nextRepeat = repeat + 1
+					if nextRepeat > maxRepeat {
+						continue
+					}
+				}
+				nextClassRepeat := 1
+				if trackClassRepeat && modes[nextChar] == modes[char] {
+					nextClassRepeat = classRepeat + 1
+					if nextClassRepeat > maxClassRepeat {
+						continue
+					}
+				}
+				nextSequence, nextDirection := 1, 0
+				if trackSequence {
+					ascending := follows[char][nextChar]
+					descending := follows[nextChar][char]
+					switch {
+					case ascending && direction == 1, descending && direction == 2:
+						nextSequence, nextDirection = sequence+1, direction
+					case ascending:
+						nextSequence, nextDirection = 2, 1
+					case descending:
+						nextSequence, nextDirection = 2, 2
+					}
+					if nextSequence > int(g.config.MaxSequence) {
+						continue
+					}
+					nextSequence = min(nextSequence, maxSequence)
+				}
+				next[index(nextChar, nextRepeat, nextClassRepeat, nextSequence, nextDirection)] +=
+					probability * probabilities[nextChar]
+			}
+		}
+		current = next
+		result[position+1] = total(current)
+	}
+	return result
+}
+
+// probabilityMinClasses returns the probability that a random string of the given
+// length, constructed from the given characters with the given probabilities, contains at least the
+// minimum amount of different character modes of the generator's configuration
+func (g *Generator) probabilityMinClasses(chars []string, probabilities []float64, length int) float64 {
+	if g.config.MinClasses <= 0 {
+		return 1
+	}
+	shares := make(map[Mode]float64)
+	for i, char := range chars {
+		shares[g.modeOfChar([]rune(char)[0])] += probabilities[i]
+	}
+	var classShares []float64
+	for _, share := range shares {
+		classShares = append(classShares, share)
+	}
+
+	// Using inclusion-exclusion, exactly[subset] is the probability that the
+	// string consists of characters of exactly the classes in subset
+	subsets := 1 << len(classShares)
+	within := make([]float64, subsets)
+	for subset := 0; subset < subsets; subset++ {
+		share := 0.0
+		for i, classShare := range classShares {
+			if subset&(1<<i) != 0 {
+				share += classShare
+			}
+		}
+		within[subset] = math.Pow(min(share, 1), float64(length))
+	}
+	var total float64
+	for subset := 0; subset < subsets; subset++ {
+		if int64(bits.OnesCount(uint(subset))) < g.config.MinClasses {
+			continue
+		}
+		exactly := 0.0
+		for sub := subset; ; sub = (sub - 1) & subset {
+			if (bits.OnesCount(uint(subset))-bits.OnesCount(uint(sub)))%2 == 0 {
+				exactly += within[sub]
+			} else {
+				exactly -= within[sub]
+			}
+			if sub == 0 {
+				break
+			}
+		}
+		total += exactly
+	}
+	return max(total, 0)
+}
+
+// probabilityModeAmounts returns the probability that a random string of the given
+// length, constructed from the given characters with the given probabilities, meets
+// the minimum and maximum amounts of characters per character mode of the
+// generator's configuration
+func (g *Generator) probabilityModeAmounts(chars []string, probabilities []float64, length int) float64 {
+	type modeClass struct {
+		share     float64
+		minAmount int
+		maxAmount int
+	}
+	var classes []modeClass
+	shares := make(map[Mode]float64)
+	for i, char := range chars {
+		shares[g.modeOfChar([]rune(char)[0])] += probabilities[i]
+	}
+	constrained := false
+	for _, mode := range append([]Mode{0}, charModes...) {
+		minAmount, maxAmount := g.amountsForMode(mode)
+		if minAmount > 0 || maxAmount > 0 {
+			constrained = true
+		}
+		if maxAmount <= 0 {
+			maxAmount = int64(length)
+		}
+		if shares[mode] == 0 {
+			if minAmount > 0 {
+				return 0
+			}
+			continue
+		}
+		classes = append(classes, modeClass{shares[mode], int(minAmount), int(maxAmount)})
+	}
+	if !constrained {
+		return 1
+	}
+
+	// fits holds the probability that the remaining classes meet their constraints
+	// when they are distributed over the given amount of remaining positions
+	fits := make([]float64, length+1)
+	last := classes[len(classes)-1]
+	for remaining := last.minAmount; remaining <= min(last.maxAmount, length); remaining++ {
+		fits[remaining] = 1
+	}
+	remainingShare := last.share
+	for i := len(classes) - 2; i >= 0; i-- {
+		class := classes[i]
+		remainingShare += class.share
+		share := class.share / remainingShare
+		next := make([]float64, length+1)
+		for remaining := 0; remaining <= length; remaining++ {
+			for amount := class.minAmount; amount <= min(class.maxAmount, remaining); amount++ {
+				if fits[remaining-amount] == 0 {
+					continue
+				}
+				next[remaining] += binomialProbability(remaining, amount, share) * fits[remaining-amount]
+			}
+		}
+		fits = next
+	}
+	return fits[length]
+}
+
+// probabilityClassAmounts returns the probability that a random string of the given
+// length, constructed from the given characters with the given probabilities, meets
+// the minimum amounts of the custom character classes of the generator's configuration
+func (g *Generator) probabilityClassAmounts(chars []string, probabilities []float64, length int) float64 {
+	probability := 1.0
+	for _, class := range g.config.CharClasses {
+		if class.MinAmount <= 0 {
+			continue
+		}
+		classChars := graphemeClusters(norm.NFC.String(class.Chars))
+		share := 0.0
+		for i, char := range chars {
+			if slices.Contains(classChars, char) {
+				share += probabilities[i]
+			}
+		}
+		var classProbability float64
+		for amount := int(class.MinAmount); amount <= length; amount++ {
+			classProbability += binomialProbability(length, amount, share)
+		}
+		probability *= classProbability
+	}
+	return probability
+}
+
+// probabilityUnique returns the probability that a random string of the given
+// length, constructed from the given characters with the given probabilities,
+// consists of unique characters only, if the generator's configuration requires it.
+// Characters with the same probability are grouped, so that the probability can be
+// calculated from the amount of characters that are selected from each group
+func (g *Generator) probabilityUnique(chars []string, probabilities []float64, length int) float64 {
+	if !g.config.UniqueChars {
+		return 1
+	}
+	type uniqueGroup struct {
+		size  int
+		share float64
+	}
+	var groups []uniqueGroup
+	sizes := make(map[float64]int)
+	for _, probability := range probabilities {
+		if sizes[probability] == 0 {
+			groups = append(groups, uniqueGroup{share: probability})
+		}
+		sizes[probability]++
+	}
+	for i := range groups {
+		groups[i].size = sizes[groups[i].share]
+		groups[i].share *= float64(groups[i].size)
+	}
+
+	// uniqueWithin returns the probability that the given amount of characters,
+	// selected with equal probability from a group of the given size, are unique
+	uniqueWithin := func(size, amount int) float64 {
+		probability := 1.0
+		for i := 0; i < amount; i++ {
+			probability *= float64(size-i) / float64(size)
+		}
+		return probability
+	}
+
+	// fits holds the probability that the characters on the given amount of
+	// remaining positions that are selected from the remaining groups are unique
+	fits := make([]float64, length+1)
+	last := groups[len(groups)-1]
+	for remaining := 0; remaining <= length; remaining++ {
+		fits[remaining] = uniqueWithin(last.size, remaining)
+	}
+	remainingShare := last.share
+	for i := len(groups) - 2; i >= 0; i-- {
+		group := groups[i]
+		remainingShare += group.share
+		share := group.share / remainingShare
+		next := make([]float64, length+1)
+		for remaining := 0; remaining <= length; remaining++ {
+			for amount := 0; amount <= min(group.size, remaining); amount++ {
+				next[remaining] += binomialProbability(remaining, amount, share) *
+					uniqueWithin(group.size, amount) * fits[remaining-amount]
+			}
+		}
+		fits = next
+	}
+	return fits[length]
+}
+
+// log2BigInt returns the base 2 logarithm of the given positive big.Int
+func log2BigInt(value *big.Int) float64 {
+	shift := max(value.BitLen()-64, 0)
+	mantissa := new(big.Int).Rsh(value, uint(shift))
+	return math.Log2(float64(mantissa.Uint64())) + float64(shift)
+}
+
+// binomialProbability returns the probability of exactly k successes in n trials
+// with the given success probability p
+func binomialProbability(n, k int, p float64) float64 {
+	switch {
+	case p >= 1:
+		if k == n {
+			return 1
+		}
+		return 0
+	case p <= 0:
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}
+	logN, _ := math.Lgamma(float64(n + 1))
+	logK, _ := math.Lgamma(float64(k + 1))
+	logNK, _ := math.Lgamma(float64(n - k + 1))
+	return math.Exp(logN - logK - logNK + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+}
+
+// sequenceRelation returns a matrix that indicates if the second character follows
+// the first character in ascending order in any of the sequences in sequenceRanges.
+// Characters that consist of multiple code points are never part of a sequence
+func sequenceRelation(chars []string) [][]bool {
+	follows := make([][]bool, len(chars))
+	for i := range chars {
+		follows[i] = make([]bool, len(chars))
+	}
+	for i, char := range chars {
+		for j, nextChar := range chars {
+			if utf8.RuneCountInString(char) != 1 || utf8.RuneCountInString(nextChar) != 1 {
+				continue
+			}
+			for _, sequence := range sequenceRanges {
+				prev := strings.IndexRune(sequence, unicode.ToLower([]rune(char)[0]))
+				next := strings.IndexRune(sequence, unicode.ToLower([]rune(nextChar)[0]))
+				if prev >= 0 && next >= 0 && next-prev == 1 {
+					follows[i][j] = true
+					break
+				}
+			}
+		}
+	}
+	return follows
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestGenerator_Entropy(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want float64
+	}{
+		{"CoinFlip", []Option{WithAlgorithm(AlgoCoinFlip)}, 1},
+		{"Binary default", []Option{WithAlgorithm(AlgoBinary)}, 256},
+		{"Binary fixed length", []Option{WithAlgorithm(AlgoBinary), WithFixedLength(16)}, 128},
+		{"Random PIN", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric),
+			WithFixedLength(6),
+		}, 6 * math.Log2(10)},
+		{"Random length range", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric),
+			WithMinLength(4), WithMaxLength(5),
+		}, 1 + 4.5*math.Log2(10)},
+		{"Random with MaxRepeat", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric),
+			WithFixedLength(3), WithMaxRepeat(1),
+		}, math.Log2(10 * 9 * 9)},
+		{"Random length range with MaxRepeat", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric),
+			WithMinLength(2), WithMaxLength(3), WithMaxRepeat(1),
+		}, 1 + (math.Log2(10*9)+math.Log2(10*9*9))/2},
+		{"Random with UniqueChars", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric),
+			WithFixedLength(3), WithUniqueChars(),
+		}, math.Log2(10 * 9 * 8)},
+		{"Random with MinNumeric", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric | ModeLowerCase),
+			WithFixedLength(2), WithMinNumeric(1),
+		}, math.Log2(36*36 - 26*26)},
+		{"Random with MaxNumeric", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric | ModeLowerCase),
+			WithFixedLength(2), WithMaxNumeric(1),
+		}, math.Log2(36*36 - 10*10)},
+		{"Random with FirstCharMode", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric | ModeLowerCase),
+			WithFixedLength(2), WithFirstCharMode(ModeLowerCase),
+		}, math.Log2(26 * 36)},
+		{"Random with MinClasses", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric | ModeLowerCase),
+			WithFixedLength(2), WithMinClasses(2),
+		}, math.Log2(2 * 10 * 26)},
+		{"Random with MaxClassRepeat", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric | ModeLowerCase),
+			WithFixedLength(3), WithMaxClassRepeat(1),
+		}, math.Log2(10*26*10 + 26*10*26)},
+		{"Random with MaxSequence", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric),
+			WithExcludeChars("056789"), WithFixedLength(2), WithMaxSequence(1),
+		}, math.Log2(16 - 6)},
+		{"Random with flag emojis", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(0),
+			WithCharClass("flags", "🇩🇪🇫🇷🇮🇹🇪🇸", 0), WithFixedLength(4),
+		}, 4 * math.Log2(4)},
+		{"Random with emoji sequences", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(0),
+			WithCharClass("emojis", "👍🏽👨‍👩‍👧🇩🇪🇫🇷", 0), WithFixedLength(4),
+		}, 4 * math.Log2(4)},
+		{"Random with unique flag emojis", []Option{
+			WithAlgorithm(AlgoRandom), WithModeMask(0),
+			WithCharClass("flags", "🇩🇪🇫🇷🇮🇹🇪🇸", 0), WithFixedLength(4), WithUniqueChars(),
+		}, math.Log2(4 * 3 * 2 * 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(NewConfig(tt.opts...))
+			got, err := g.Entropy()
+			if err != nil {
+				t.Fatalf("Entropy() failed: %s", err)
+			}
+			if math.Abs(got-tt.want) > 0.0001 {
+				t.Errorf("Entropy() failed, expected: %f, got: %f", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerator_Entropy_constraintsLowerEntropy(t *testing.T) {
+	base := New(NewConfig(WithAlgorithm(AlgoRandom), WithFixedLength(16)))
+	constrained := New(NewConfig(WithAlgorithm(AlgoRandom), WithFixedLength(16),
+		WithMaxRepeat(1), WithMaxSequence(2), WithFirstCharMode(ModeUpperCase)))
+	baseEntropy, err := base.Entropy()
+	if err != nil {
+		t.Fatalf("Entropy() failed: %s", err)
+	}
+	constrainedEntropy, err := constrained.Entropy()
+	if err != nil {
+		t.Fatalf("Entropy() failed: %s", err)
+	}
+	if constrainedEntropy >= baseEntropy {
+		t.Errorf("Entropy() with constraints expected to be lower than %f, got: %f",
+			baseEntropy, constrainedEntropy)
+	}
+}
+
+func TestGenerator_Entropy_pronounceable(t *testing.T) {
+	g := New(NewConfig(WithAlgorithm(AlgoPronounceable)))
+	entropy, err := g.Entropy()
+	if err != nil {
+		t.Fatalf("Entropy() failed: %s", err)
+	}
+	if entropy <= 0 {
+		t.Errorf("Entropy() expected positive entropy, got: %f", entropy)
+	}
+}
+
+func TestGenerator_Entropy_fails(t *testing.T) {
+	g := New(NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeNumeric),
+		WithFixedLength(11), WithUniqueChars()))
+	if _, err := g.Entropy(); !errors.Is(err, ErrUnsatisfiableConstraints) {
+		t.Errorf("Entropy() expected ErrUnsatisfiableConstraints, got: %s", err)
+	}
+	g = New(NewConfig(WithAlgorithm(AlgoUnsupported)))
+	if _, err := g.Entropy(); err == nil {
+		t.Error("Entropy() with unsupported algorithm expected to fail")
+	}
+}
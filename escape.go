@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EscapeContext represents a target context in which a password is used, i. e. a
+// shell script or a SQL statement
+type EscapeContext uint8
+
+const (
+	// EscapeNone represents no specific context. Passwords are not escaped
+	EscapeNone EscapeContext = iota
+	// EscapeShell represents a single-quoted string of a POSIX shell
+	EscapeShell
+	// EscapePowerShell represents a single-quoted (verbatim) PowerShell string
+	EscapePowerShell
+	// EscapeJSON represents a JSON string
+	EscapeJSON
+	// EscapeYAML represents a double-quoted YAML scalar
+	EscapeYAML
+	// EscapeSQL represents a standard (ANSI) SQL string literal, in which only the
+	// single quote is escaped. Backslashes are kept as is, so the literal is not
+	// valid for databases that treat the backslash as escape character by default
+	// (i. e. MySQL without the NO_BACKSLASH_ESCAPES SQL mode)
+	EscapeSQL
+	// EscapeXML represents a double-quoted XML attribute value
+	EscapeXML
+	// EscapeURL represents a percent-encoded URL component
+	EscapeURL
+)
+
+var (
+	// ErrUnknownEscapeContext is returned if an escape context name is not known
+	ErrUnknownEscapeContext = errors.New("unknown escape context")
+	// ErrUnrepresentableChar is returned if a password contains a character that can
+	// not be represented in an escape context, not even escaped
+	ErrUnrepresentableChar = errors.New("character can not be represented")
+)
+
+// powerShellQuotes are the characters that PowerShell treats as single quotes
+const powerShellQuotes = "'‘’‚‛"
+
+// EscapeContextFromString returns the EscapeContext for the given name. Valid names
+// are "none", "sh" (or "shell" and "posix"), "powershell" (or "pwsh" and "ps"),
+// "json", "yaml" (or "yml"), "sql", "xml" and "url"
+func EscapeContextFromString(context string) (EscapeContext, error) {
+	switch strings.ToLower(strings.TrimSpace(context)) {
+	case "none":
+		return EscapeNone, nil
+	case "sh", "shell", "posix":
+		return EscapeShell, nil
+	case "powershell", "pwsh", "ps":
+		return EscapePowerShell, nil
+	case "json":
+		return EscapeJSON, nil
+	case "yaml", "yml":
+		return EscapeYAML, nil
+	case "sql":
+		return EscapeSQL, nil
+	case "xml":
+		return EscapeXML, nil
+	case "url":
+		return EscapeURL, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownEscapeContext, context)
+	}
+}
+
+// String satisfies the fmt.Stringer interface for the EscapeContext type
+func (c EscapeContext) String() string {
+	switch c {
+	case EscapeNone:
+		return "none"
+	case EscapeShell:
+		return "sh"
+	case EscapePowerShell:
+		return "powershell"
+	case EscapeJSON:
+		return "json"
+	case EscapeYAML:
+		return "yaml"
+	case EscapeSQL:
+		return "sql"
+	case EscapeXML:
+		return "xml"
+	case EscapeURL:
+		return "url"
+	default:
+		return "unknown"
+	}
+}
+
+// IsSafe returns true if the given character can be used in the EscapeContext
+// without any escaping. For shells, characters that are special in single-quoted
+// or double-quoted strings are not safe. For JSON, YAML, SQL and XML, quotes,
+// backslashes, control characters and other characters that are escaped in the
+// context are not safe. For URLs, only the unreserved characters (letters, digits,
+// "-", ".", "_" and "~") are safe
+func (c EscapeContext) IsSafe(char rune) bool {
+	if c == EscapeNone {
+		return true
+	}
+	if unicode.IsControl(char) {
+		return false
+	}
+	switch c {
+	case EscapeShell:
+		return !unicode.IsSpace(char) && !strings.ContainsRune("'\"$`\\!", char)
+	case EscapePowerShell:
+		return !unicode.IsSpace(char) && !strings.ContainsRune(powerShellQuotes+"\"“”„$`", char)
+	case EscapeJSON:
+		return char != '"' && char != '\\' && char != '\u2028' && char != '\u2029'
+	case EscapeYAML:
+		return char != '"' && char != '\\' && strconv.IsPrint(char)
+	case EscapeSQL:
+		return char != '\'' && char != '\\'
+	case EscapeXML:
+		return !strings.ContainsRune("&<>\"'", char)
+	case EscapeURL:
+		return char <= unicode.MaxASCII && (unicode.IsLetter(char) || unicode.IsDigit(char) ||
+			strings.ContainsRune("-._~", char))
+	default:
+		return false
+	}
+}
+
+// Escape returns the given password quoted and escaped for the EscapeContext, so
+// that it can be pasted into a script, statement or document of the context as is.
+// An error is returned if the password contains a character that can not be
+// represented in the context at all, like a NUL character in a shell argument
+func Escape(password string, context EscapeContext) (string, error) {
+	switch context {
+	case EscapeNone:
+		return password, nil
+	case EscapeShell:
+		if strings.ContainsRune(password, 0) {
+			return "", fmt.Errorf("%w in shell strings: NUL", ErrUnrepresentableChar)
+		}
+		return "'" + strings.ReplaceAll(password, "'", `'\''`) + "'", nil
+	case EscapePowerShell:
+		if strings.ContainsRune(password, 0) {
+			return "", fmt.Errorf("%w in PowerShell strings: NUL", ErrUnrepresentableChar)
+		}
+		escaped := strings.Builder{}
+		escaped.WriteRune('\'')
+		for _, char := range password {
+			if strings.ContainsRune(powerShellQuotes, char) {
+				escaped.WriteRune(char)
+			}
+			escaped.WriteRune(char)
+		}
+		escaped.WriteRune('\'')
+		return escaped.String(), nil
+	case EscapeJSON:
+		escaped := strings.Builder{}
+		encoder := json.NewEncoder(&escaped)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(password); err != nil {
+			return "", fmt.Errorf("failed to encode JSON string: %w", err)
+		}
+		return strings.TrimSuffix(escaped.String(), "\n"), nil
+	case EscapeYAML:
+		return strconv.Quote(password), nil
+	case EscapeSQL:
+		if strings.ContainsRune(password, 0) {
+			return "", fmt.Errorf("%w in SQL strings: NUL", ErrUnrepresentableChar)
+		}
+		return "'" + strings.ReplaceAll(password, "'", "''") + "'", nil
+	case EscapeXML:
+		return escapeXMLAttribute(password)
+	case EscapeURL:
+		escaped := strings.Builder{}
+		for _, char := range password {
+			if EscapeURL.IsSafe(char) {
+				escaped.WriteRune(char)
+				continue
+			}
+			for _, octet := range []byte(string(char)) {
+				_, _ = fmt.Fprintf(&escaped, "%%%02X", octet)
+			}
+		}
+		return escaped.String(), nil
+	default:
+		return "", fmt.Errorf("%w: %d", ErrUnknownEscapeContext, context)
+	}
+}
+
+// escapeXMLAttribute returns the given password as double-quoted XML attribute value.
+// Whitespace characters are escaped as character references, so that they are not
+// normalized by XML parsers
+func escapeXMLAttribute(password string) (string, error) {
+	escaped := strings.Builder{}
+	escaped.WriteRune('"')
+	for _, char := range password {
+		switch char {
+		case '&':
+			escaped.WriteString("&amp;")
+		case '<':
+			escaped.WriteString("&lt;")
+		case '>':
+			escaped.WriteString("&gt;")
+		case '"':
+			escaped.WriteString("&quot;")
+		case '\'':
+			escaped.WriteString("&apos;")
+		case '\t', '\n', '\r':
+			_, _ = fmt.Fprintf(&escaped, "&#x%X;", char)
+		default:
+			// Characters that are not allowed in XML 1.0 documents
+			if char < 0x20 || char == 0xFFFE || char == 0xFFFF {
+				return "", fmt.Errorf("%w in XML documents: %U", ErrUnrepresentableChar, char)
+			}
+			escaped.WriteRune(char)
+		}
+	}
+	escaped.WriteRune('"')
+	return escaped.String(), nil
+}
+
+// isExcludedFunc returns a function that reports if a character must not be part of
+// generated passwords, because it is excluded, confusable or not safe for the
+// configured SafeFor context
+func (g *Generator) isExcludedFunc() func(char rune) bool {
+	excluded := NewCharset(g.config.ExcludeChars).Union(confusableChars(g.config.Confusables))
+	return func(char rune) bool {
+		return excluded.Contains(char) || !g.config.SafeFor.IsSafe(char)
+	}
+}
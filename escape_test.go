@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// escapeContexts holds all supported escape contexts
+var escapeContexts = []EscapeContext{
+	EscapeNone, EscapeShell, EscapePowerShell, EscapeJSON, EscapeYAML, EscapeSQL, EscapeXML,
+	EscapeURL,
+}
+
+func TestEscapeContextFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    EscapeContext
+		wantErr bool
+	}{
+		{"none", EscapeNone, false},
+		{"sh", EscapeShell, false},
+		{"POSIX", EscapeShell, false},
+		{"pwsh", EscapePowerShell, false},
+		{"json", EscapeJSON, false},
+		{" yml ", EscapeYAML, false},
+		{"sql", EscapeSQL, false},
+		{"xml", EscapeXML, false},
+		{"url", EscapeURL, false},
+		{"csv", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EscapeContextFromString(tt.name)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownEscapeContext) {
+					t.Errorf("EscapeContextFromString() expected ErrUnknownEscapeContext, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("EscapeContextFromString() failed: %s", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EscapeContextFromString() failed, expected: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+	for _, context := range escapeContexts {
+		if got, err := EscapeContextFromString(context.String()); err != nil || got != context {
+			t.Errorf("EscapeContextFromString(%q) failed, expected: %s, got: %s", context, context, got)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	password := `a'b"c$d` + "`e\\f&g<h>i é%"
+	tests := []struct {
+		context EscapeContext
+		want    string
+	}{
+		{EscapeNone, password},
+		{EscapeShell, `'a'\''b"c$d` + "`e\\f&g<h>i é%'"},
+		{EscapePowerShell, `'a''b"c$d` + "`e\\f&g<h>i é%'"},
+		{EscapeJSON, `"a'b\"c$d` + "`e\\\\f&g<h>i é%\""},
+		{EscapeYAML, `"a'b\"c$d` + "`e\\\\f&g<h>i é%\""},
+		{EscapeSQL, `'a''b"c$d` + "`e\\f&g<h>i é%'"},
+		{EscapeXML, `"a&apos;b&quot;c$d` + "`e\\f&amp;g&lt;h&gt;i é%\""},
+		{EscapeURL, "a%27b%22c%24d%60e%5Cf%26g%3Ch%3Ei%20%C3%A9%25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.context.String(), func(t *testing.T) {
+			got, err := Escape(password, tt.context)
+			if err != nil {
+				t.Fatalf("Escape() failed: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("Escape() failed, expected: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEscape_roundTrip(t *testing.T) {
+	password := "a'b\"c\\d\te f"
+	escaped, err := Escape(password, EscapeJSON)
+	if err != nil {
+		t.Fatalf("Escape() failed: %s", err)
+	}
+	var decoded string
+	if err = json.Unmarshal([]byte(escaped), &decoded); err != nil || decoded != password {
+		t.Errorf("Escape() JSON round trip failed, expected: %q, got: %q (%v)", password, decoded, err)
+	}
+	escaped, err = Escape(password, EscapeYAML)
+	if err != nil {
+		t.Fatalf("Escape() failed: %s", err)
+	}
+	if decoded, err = strconv.Unquote(escaped); err != nil || decoded != password {
+		t.Errorf("Escape() YAML round trip failed, expected: %q, got: %q (%v)", password, decoded, err)
+	}
+}
+
+func TestEscape_unrepresentable(t *testing.T) {
+	for _, context := range []EscapeContext{EscapeShell, EscapePowerShell, EscapeSQL, EscapeXML} {
+		t.Run(context.String(), func(t *testing.T) {
+			if _, err := Escape("a\x00b", context); !errors.Is(err, ErrUnrepresentableChar) {
+				t.Errorf("Escape() expected ErrUnrepresentableChar, got: %v", err)
+			}
+		})
+	}
+	if _, err := Escape("a", EscapeContext(99)); !errors.Is(err, ErrUnknownEscapeContext) {
+		t.Errorf("Escape() expected ErrUnknownEscapeContext, got: %v", err)
+	}
+}
+
+func TestEscapeContext_IsSafe(t *testing.T) {
+	// Safe characters must not be changed by the escaping
+	candidates := CharRangeAlphaLower + CharRangeAlphaUpper + CharRangeNumeric + CharRangeSpecial +
+		" äé€\t "
+	for _, context := range escapeContexts {
+		t.Run(context.String(), func(t *testing.T) {
+			for _, char := range candidates {
+				if !context.IsSafe(char) {
+					continue
+				}
+				escaped, err := Escape(string(char), context)
+				if err != nil {
+					t.Errorf("Escape() failed for safe character %q: %s", char, err)
+					continue
+				}
+				if !strings.Contains(escaped, string(char)) || len(escaped)-len(string(char)) > 2 {
+					t.Errorf("IsSafe() failed, safe character %q is escaped as %s", char, escaped)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_safeFor(t *testing.T) {
+	for _, context := range escapeContexts {
+		t.Run(context.String(), func(t *testing.T) {
+			config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeNumeric|
+				ModeSpecial|ModeUpperCase), WithFixedLength(64), WithSafeFor(context))
+			generator := New(config)
+			for range 20 {
+				password, err := generator.Generate()
+				if err != nil {
+					t.Fatalf("Generate() failed: %s", err)
+				}
+				for _, char := range password {
+					if !context.IsSafe(char) {
+						t.Errorf("Generate() failed, password %q contains unsafe character %q", password, char)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Entropy_safeFor(t *testing.T) {
+	var mask ModeMask = ModeLowerCase | ModeNumeric | ModeSpecial | ModeUpperCase
+	full, err := New(NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(mask), WithFixedLength(16))).Entropy()
+	if err != nil {
+		t.Fatalf("Entropy() failed: %s", err)
+	}
+	safe, err := New(NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(mask), WithFixedLength(16),
+		WithSafeFor(EscapeURL))).Entropy()
+	if err != nil {
+		t.Fatalf("Entropy() failed: %s", err)
+	}
+	if safe >= full {
+		t.Errorf("Entropy() failed, expected the safe-for entropy %.2f to be lower than %.2f", safe, full)
+	}
+}
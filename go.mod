@@ -6,6 +6,10 @@ module github.com/wneessen/apg-go
 
 go 1.22
 
-require github.com/wneessen/go-hibp v1.1.0
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/wneessen/go-hibp v1.1.0
+	golang.org/x/text v0.21.0
+)
 
 require github.com/wneessen/niljson v0.1.0 // indirect
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"unicode"
+)
+
+const (
+	// zeroWidthJoiner joins two characters into a single grapheme (i. e. in emoji sequences)
+	zeroWidthJoiner = '\u200d'
+	// emojiModifierFirst and emojiModifierLast represent the range of emoji skin tone modifiers
+	emojiModifierFirst = '\U0001f3fb'
+	emojiModifierLast  = '\U0001f3ff'
+	// regionalIndicatorFirst and regionalIndicatorLast represent the range of regional
+	// indicator symbols, that are combined in pairs to flag emojis
+	regionalIndicatorFirst = '\U0001f1e6'
+	regionalIndicatorLast  = '\U0001f1ff'
+	// tagFirst and tagLast represent the range of tag characters used in emoji tag sequences
+	tagFirst = '\U000e0020'
+	tagLast  = '\U000e007f'
+)
+
+// graphemeClusters splits the given string into user-perceived characters (grapheme
+// clusters). Combining marks, variation selectors, emoji modifiers, tag sequences,
+// zero-width joiner sequences and regional indicator pairs are kept together with
+// their base character. This covers the extended grapheme cluster rules that are
+// relevant for password character ranges, but is not a full implementation of UAX #29
+func graphemeClusters(input string) []string {
+	var clusters []string
+	var current []rune
+	joinNext := false
+	for _, char := range input {
+		switch {
+		case len(current) == 0, joinNext, isGraphemeExtender(char):
+		case isRegionalIndicator(char) && len(current) == 1 && isRegionalIndicator(current[0]):
+		default:
+			clusters = append(clusters, string(current))
+			current = current[:0:0]
+		}
+		current = append(current, char)
+		joinNext = char == zeroWidthJoiner
+	}
+	if len(current) > 0 {
+		clusters = append(clusters, string(current))
+	}
+	return clusters
+}
+
+// isGraphemeExtender returns true if the given character extends the grapheme cluster
+// of the preceding character
+func isGraphemeExtender(char rune) bool {
+	return unicode.In(char, unicode.Mn, unicode.Me, unicode.Mc, unicode.Variation_Selector) ||
+		char == zeroWidthJoiner ||
+		(char >= emojiModifierFirst && char <= emojiModifierLast) ||
+		(char >= tagFirst && char <= tagLast)
+}
+
+// isRegionalIndicator returns true if the given character is a regional indicator symbol
+func isRegionalIndicator(char rune) bool {
+	return char >= regionalIndicatorFirst && char <= regionalIndicatorLast
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"testing"
+)
+
+func TestGraphemeClusters(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"Empty", "", nil},
+		{"ASCII", "abc", []string{"a", "b", "c"}},
+		{"Multi-byte characters", "äöü", []string{"ä", "ö", "ü"}},
+		{"Combining mark", "éa", []string{"é", "a"}},
+		{"Emoji modifier", "👍🏽👍", []string{"👍🏽", "👍"}},
+		{"Zero-width joiner sequence", "👨‍👩‍👧a", []string{"👨‍👩‍👧", "a"}},
+		{"Variation selector", "❤️x", []string{"❤️", "x"}},
+		{"Regional indicators", "🇩🇪🇫🇷🇮", []string{"🇩🇪", "🇫🇷", "🇮"}},
+		{"Keycap", "1️⃣2", []string{"1️⃣", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusters := graphemeClusters(tt.input)
+			if len(clusters) != len(tt.want) {
+				t.Fatalf("graphemeClusters() failed, expected: %q, got: %q", tt.want, clusters)
+			}
+			for i := range clusters {
+				if clusters[i] != tt.want[i] {
+					t.Errorf("graphemeClusters() failed, expected: %q, got: %q", tt.want[i], clusters[i])
+				}
+			}
+		})
+	}
+}
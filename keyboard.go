@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeyboardLayout represents a keyboard layout
+type KeyboardLayout string
+
+const (
+	// KeyboardLayoutCH represents the Swiss (German) QWERTZ keyboard layout
+	KeyboardLayoutCH KeyboardLayout = "ch"
+	// KeyboardLayoutDE represents the German QWERTZ keyboard layout
+	KeyboardLayoutDE KeyboardLayout = "de"
+	// KeyboardLayoutFR represents the French AZERTY keyboard layout
+	KeyboardLayoutFR KeyboardLayout = "fr"
+	// KeyboardLayoutNordic represents the Nordic (Swedish/Finnish) QWERTY keyboard layout
+	KeyboardLayoutNordic KeyboardLayout = "nordic"
+	// KeyboardLayoutUK represents the British QWERTY keyboard layout
+	KeyboardLayoutUK KeyboardLayout = "uk"
+	// KeyboardLayoutUS represents the US QWERTY keyboard layout
+	KeyboardLayoutUS KeyboardLayout = "us"
+)
+
+// noKey represents a position of a keyboard layout that does not produce a character
+// directly, i. e. because it is a dead key or the key does not exist in the layout
+const noKey = '\x00'
+
+// ErrUnknownKeyboardLayout is returned if a keyboard layout is not known
+var ErrUnknownKeyboardLayout = errors.New("unknown keyboard layout")
+
+// keyboardRows holds the characters of a keyboard layout. Each row consists of the
+// characters without modifier (first string) and with the shift modifier (second
+// string) in the order of the physical keys of the row. The rows are:
+//
+//   - the number row: the key left of "1", the keys "1" to "0" and the 2 keys right of it
+//   - the upper letter row: 10 letter keys and the 2 keys right of it
+//   - the home row: 9 letter keys, the 2 keys right of it and the key left of Enter
+//     (the backslash key on US keyboards)
+//   - the lower row: the key right of the left shift on ISO keyboards, 7 letter keys
+//     and the 3 keys right of it
+//
+// Dead keys and missing keys are represented by noKey.
+type keyboardRows [4][2]string
+
+// keyboardLayouts holds the characters of all supported keyboard layouts
+var keyboardLayouts = map[KeyboardLayout]keyboardRows{
+	KeyboardLayoutCH: {
+		{"§1234567890'\x00", "°+\"*ç%&/()=?\x00"},
+		{"qwertzuiopü\x00", "QWERTZUIOPè!"},
+		{"asdfghjklöä$", "ASDFGHJKLéà£"},
+		{"<yxcvbnm,.-", ">YXCVBNM;:_"},
+	},
+	KeyboardLayoutDE: {
+		{"\x001234567890ß\x00", "°!\"§$%&/()=?\x00"},
+		{"qwertzuiopü+", "QWERTZUIOPÜ*"},
+		{"asdfghjklöä#", "ASDFGHJKLÖÄ'"},
+		{"<yxcvbnm,.-", ">YXCVBNM;:_"},
+	},
+	KeyboardLayoutFR: {
+		{"²&é\"'(-è_çà)=", "\x001234567890°+"},
+		{"azertyuiop\x00$", "AZERTYUIOP\x00£"},
+		{"qsdfghjklmù*", "QSDFGHJKLM%µ"},
+		{"<wxcvbn,;:!", ">WXCVBN?./§"},
+	},
+	KeyboardLayoutNordic: {
+		{"§1234567890+\x00", "½!\"#¤%&/()=?\x00"},
+		{"qwertyuiopå\x00", "QWERTYUIOPÅ\x00"},
+		{"asdfghjklöä'", "ASDFGHJKLÖÄ*"},
+		{"<zxcvbnm,.-", ">ZXCVBNM;:_"},
+	},
+	KeyboardLayoutUK: {
+		{"`1234567890-=", "¬!\"£$%^&*()_+"},
+		{"qwertyuiop[]", "QWERTYUIOP{}"},
+		{"asdfghjkl;'#", "ASDFGHJKL:@~"},
+		{"\\zxcvbnm,./", "|ZXCVBNM<>?"},
+	},
+	KeyboardLayoutUS: {
+		{"`1234567890-=", "~!@#$%^&*()_+"},
+		{"qwertyuiop[]", "QWERTYUIOP{}"},
+		{"asdfghjkl;'\\", "ASDFGHJKL:\"|"},
+		{"\x00zxcvbnm,./", "\x00ZXCVBNM<>?"},
+	},
+}
+
+// KeyboardLayoutsFromString parses a comma-separated list of keyboard layout names
+// (i. e. "us,de") and returns the corresponding list of KeyboardLayout. If a layout
+// is unknown, an error wrapping ErrUnknownKeyboardLayout is returned
+func KeyboardLayoutsFromString(names string) ([]KeyboardLayout, error) {
+	var layouts []KeyboardLayout
+	for _, name := range strings.Split(names, ",") {
+		layout := KeyboardLayout(strings.ToLower(strings.TrimSpace(name)))
+		if layout == "" {
+			continue
+		}
+		if _, ok := keyboardLayouts[layout]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownKeyboardLayout, layout)
+		}
+		layouts = append(layouts, layout)
+	}
+	return layouts, nil
+}
+
+// KeyboardSafeCharset returns the Charset of all characters that are on the same key
+// with the same modifier in all of the given keyboard layouts. Only characters that
+// can be typed without a modifier or with the shift modifier are considered, dead
+// keys are never part of the Charset. If a layout is unknown, an error wrapping
+// ErrUnknownKeyboardLayout is returned
+func KeyboardSafeCharset(layouts ...KeyboardLayout) (Charset, error) {
+	if len(layouts) == 0 {
+		return Charset{}, nil
+	}
+	rows := make([]keyboardRows, 0, len(layouts))
+	for _, layout := range layouts {
+		layoutRows, ok := keyboardLayouts[layout]
+		if !ok {
+			return Charset{}, fmt.Errorf("%w: %q", ErrUnknownKeyboardLayout, layout)
+		}
+		rows = append(rows, layoutRows)
+	}
+
+	var chars strings.Builder
+	for row := range rows[0] {
+		for level := range rows[0][row] {
+			keys := make([][]rune, len(rows))
+			for i := range rows {
+				keys[i] = []rune(rows[i][row][level])
+			}
+			for position, char := range keys[0] {
+				if char == noKey {
+					continue
+				}
+				safe := true
+				for _, layoutKeys := range keys[1:] {
+					if layoutKeys[position] != char {
+						safe = false
+						break
+					}
+				}
+				if safe {
+					chars.WriteRune(char)
+				}
+			}
+		}
+	}
+	return NewCharset(chars.String()), nil
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeyboardLayouts_rows(t *testing.T) {
+	reference := keyboardLayouts[KeyboardLayoutUS]
+	for name, rows := range keyboardLayouts {
+		for row := range rows {
+			for level := range rows[row] {
+				got, want := len([]rune(rows[row][level])), len([]rune(reference[row][level]))
+				if got != want {
+					t.Errorf("keyboard layout %s row %d level %d failed, expected: %d keys, got: %d",
+						name, row, level, want, got)
+				}
+			}
+		}
+	}
+}
+
+func TestKeyboardLayoutsFromString(t *testing.T) {
+	layouts, err := KeyboardLayoutsFromString("US, de,,Nordic")
+	if err != nil {
+		t.Fatalf("KeyboardLayoutsFromString() failed: %s", err)
+	}
+	want := []KeyboardLayout{KeyboardLayoutUS, KeyboardLayoutDE, KeyboardLayoutNordic}
+	if len(layouts) != len(want) {
+		t.Fatalf("KeyboardLayoutsFromString() failed, expected: %v, got: %v", want, layouts)
+	}
+	for i := range layouts {
+		if layouts[i] != want[i] {
+			t.Errorf("KeyboardLayoutsFromString() failed, expected: %s, got: %s", want[i], layouts[i])
+		}
+	}
+	if _, err = KeyboardLayoutsFromString("us,xx"); !errors.Is(err, ErrUnknownKeyboardLayout) {
+		t.Errorf("KeyboardLayoutsFromString() failed, expected error: %s, got: %s", ErrUnknownKeyboardLayout, err)
+	}
+}
+
+func TestKeyboardSafeCharset(t *testing.T) {
+	tests := []struct {
+		name     string
+		layouts  []KeyboardLayout
+		included string
+		excluded string
+	}{
+		{"US only", []KeyboardLayout{KeyboardLayoutUS}, CharRangeAlphaLower + CharRangeSpecial, ""},
+		{"US and UK", []KeyboardLayout{KeyboardLayoutUS, KeyboardLayoutUK}, "abcxyz!$%&*()", "@\"#~\\|"},
+		{"US and DE", []KeyboardLayout{KeyboardLayoutUS, KeyboardLayoutDE}, "abcx1234567890!$%.,", "yzYZ@\"^&-=;:"},
+		{"DE and CH", []KeyboardLayout{KeyboardLayoutDE, KeyboardLayoutCH}, "yzYZ\"%&/()=?", "^`'!+*"},
+		{"US and FR", []KeyboardLayout{KeyboardLayoutUS, KeyboardLayoutFR}, "bcBC", "aqzwmAQZWM1234567890"},
+		{"No dead keys", []KeyboardLayout{KeyboardLayoutDE}, "ß<>", "^´`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charset, err := KeyboardSafeCharset(tt.layouts...)
+			if err != nil {
+				t.Fatalf("KeyboardSafeCharset() failed: %s", err)
+			}
+			for _, char := range tt.included {
+				if !charset.Contains(char) {
+					t.Errorf("KeyboardSafeCharset() failed, expected charset to contain %q", char)
+				}
+			}
+			for _, char := range tt.excluded {
+				if charset.Contains(char) {
+					t.Errorf("KeyboardSafeCharset() failed, expected charset to not contain %q", char)
+				}
+			}
+			if strings.ContainsRune(charset.String(), noKey) {
+				t.Errorf("KeyboardSafeCharset() failed, charset contains dead or missing keys")
+			}
+		})
+	}
+	if _, err := KeyboardSafeCharset(KeyboardLayoutUS, "xx"); !errors.Is(err, ErrUnknownKeyboardLayout) {
+		t.Errorf("KeyboardSafeCharset() failed, expected error: %s, got: %s", ErrUnknownKeyboardLayout, err)
+	}
+}
+
+func TestGenerator_GenerateKeyboardLayouts(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithKeyboardLayouts(KeyboardLayoutUS, KeyboardLayoutDE),
+		WithModeMask(ModeLowerCase|ModeNumeric|ModeSpecial|ModeUpperCase))
+	generator := New(config)
+	safe, err := KeyboardSafeCharset(KeyboardLayoutUS, KeyboardLayoutDE)
+	if err != nil {
+		t.Fatalf("KeyboardSafeCharset() failed: %s", err)
+	}
+	for range 100 {
+		password, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		for _, char := range password {
+			if !safe.Contains(char) {
+				t.Errorf("Generate() failed, password %q contains unsafe character %q", password, char)
+			}
+		}
+	}
+
+	generator = New(NewConfig(WithAlgorithm(AlgoRandom), WithKeyboardLayouts("xx")))
+	if _, err = generator.Generate(); !errors.Is(err, ErrUnknownKeyboardLayout) {
+		t.Errorf("Generate() failed, expected error: %s, got: %s", ErrUnknownKeyboardLayout, err)
+	}
+}
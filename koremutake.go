@@ -4,6 +4,15 @@
 
 package apg
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
 // KoremutakeSyllables is a slightly modified Koremutake syllables list based on
 // the mechanism described on https://shorl.com/koremutake.php
 var KoremutakeSyllables = []string{
@@ -18,11 +27,86 @@ var KoremutakeSyllables = []string{
 	"dri", "dro", "dru", "dry", "fra", "fre", "fri", "fro", "fru", "fry", "gra",
 	"gre", "gri", "gro", "gru", "gry", "pra", "pre", "pri", "pro", "pru",
 	"pry", "sta", "ste", "sti", "sto", "stu", "sty", "tra", "tre", "er", "ed",
-	"in", "ex", "al", "en", "an", "ad", "or", "at", "ca", "ap", "el", "ci", "an",
+	"in", "ex", "al", "en", "an", "ad", "or", "at", "ca", "ap", "el", "ci",
 	"et", "it", "ob", "of", "af", "au", "cy", "im", "op", "co", "up", "ing",
 	"con", "ter", "com", "per", "ble", "der", "cal", "man", "est", "for", "mer",
-	"col", "ful", "get", "low", "son", "tle", "day", "pen", "pre", "ten",
+	"col", "ful", "get", "low", "son", "tle", "day", "pen", "ten",
 	"tor", "ver", "ber", "can", "ple", "fer", "gen", "den", "mag", "sub", "sur",
 	"men", "min", "out", "tal", "but", "cit", "cle", "cov", "dif", "ern",
-	"eve", "hap", "ket", "nal", "sup", "ted", "tem", "tin", "tro", "tro",
+	"eve", "hap", "ket", "nal", "sup", "ted", "tem", "tin", "tro",
+}
+
+// koremutakeTable is the canonical table of the 128 Koremutake syllables, as
+// described on https://shorl.com/koremutake.php. The index of a syllable in the
+// table is its numeric value
+var koremutakeTable = [128]string{
+	"ba", "be", "bi", "bo", "bu", "by", "da", "de", "di", "do", "du", "dy",
+	"fa", "fe", "fi", "fo", "fu", "fy", "ga", "ge", "gi", "go", "gu", "gy",
+	"ha", "he", "hi", "ho", "hu", "hy", "ja", "je", "ji", "jo", "ju", "jy",
+	"ka", "ke", "ki", "ko", "ku", "ky", "la", "le", "li", "lo", "lu", "ly",
+	"ma", "me", "mi", "mo", "mu", "my", "na", "ne", "ni", "no", "nu", "ny",
+	"pa", "pe", "pi", "po", "pu", "py", "ra", "re", "ri", "ro", "ru", "ry",
+	"sa", "se", "si", "so", "su", "sy", "ta", "te", "ti", "to", "tu", "ty",
+	"va", "ve", "vi", "vo", "vu", "vy", "bra", "bre", "bri", "bro", "bru", "bry",
+	"dra", "dre", "dri", "dro", "dru", "dry", "fra", "fre", "fri", "fro", "fru", "fry",
+	"gra", "gre", "gri", "gro", "gru", "gry", "pra", "pre", "pri", "pro", "pru", "pry",
+	"sta", "ste", "sti", "sto", "stu", "sty", "tra", "tre",
+}
+
+// ErrInvalidKoremutake is returned if a string is not a valid Koremutake word
+var ErrInvalidKoremutake = errors.New("invalid Koremutake word")
+
+// KoremutakeEncode converts the given number into a pronounceable Koremutake word.
+// The number is represented in base 128, with each digit being represented by the
+// corresponding syllable of the canonical Koremutake table (i. e. 10610353957 is
+// converted to "koremutake")
+func KoremutakeEncode(number uint64) string {
+	var syllables []string
+	for {
+		syllables = append(syllables, koremutakeTable[number%128])
+		number /= 128
+		if number == 0 {
+			break
+		}
+	}
+	slices.Reverse(syllables)
+	return strings.Join(syllables, "")
+}
+
+// KoremutakeDecode converts the given Koremutake word back into the number it
+// represents. The conversion is case-insensitive and ignores hyphens and
+// whitespace between the syllables. If the word contains anything other than
+// Koremutake syllables, or if the number it represents does not fit into an
+// uint64, an error wrapping ErrInvalidKoremutake is returned
+func KoremutakeDecode(word string) (uint64, error) {
+	word = strings.Map(func(char rune) rune {
+		if char == '-' || unicode.IsSpace(char) {
+			return -1
+		}
+		return unicode.ToLower(char)
+	}, word)
+	if word == "" {
+		return 0, fmt.Errorf("%w: empty word", ErrInvalidKoremutake)
+	}
+
+	var number uint64
+	for len(word) > 0 {
+		// Three letter syllables always start with two consonants, so they can't
+		// be confused with a two letter syllable
+		value, length := -1, 0
+		for index, syllable := range koremutakeTable {
+			if strings.HasPrefix(word, syllable) && len(syllable) > length {
+				value, length = index, len(syllable)
+			}
+		}
+		if value < 0 {
+			return 0, fmt.Errorf("%w: unknown syllable at %q", ErrInvalidKoremutake, word)
+		}
+		if number > (math.MaxUint64-uint64(value))/128 {
+			return 0, fmt.Errorf("%w: number exceeds the maximum value", ErrInvalidKoremutake)
+		}
+		number = number*128 + uint64(value)
+		word = word[length:]
+	}
+	return number, nil
 }
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestKoremutakeTable(t *testing.T) {
+	seen := make(map[string]struct{})
+	for _, syllable := range koremutakeTable {
+		if syllable == "" {
+			t.Fatal("koremutakeTable failed, expected 128 syllables")
+		}
+		if _, ok := seen[syllable]; ok {
+			t.Errorf("koremutakeTable failed, syllable %q is not unique", syllable)
+		}
+		seen[syllable] = struct{}{}
+	}
+}
+
+func TestKoremutakeEncode(t *testing.T) {
+	tests := []struct {
+		name   string
+		number uint64
+		want   string
+	}{
+		{"zero", 0, "ba"},
+		{"max single syllable", 127, "tre"},
+		{"two syllables", 128, "beba"},
+		{"koremutake", 10610353957, "koremutake"},
+		{"max uint64", math.MaxUint64, "betretretretretretretretretre"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KoremutakeEncode(tt.number); got != tt.want {
+				t.Errorf("KoremutakeEncode() failed, expected: %s, got: %s", tt.want, got)
+			}
+			got, err := KoremutakeDecode(tt.want)
+			if err != nil {
+				t.Fatalf("KoremutakeDecode() failed: %s", err)
+			}
+			if got != tt.number {
+				t.Errorf("KoremutakeDecode() failed, expected: %d, got: %d", tt.number, got)
+			}
+		})
+	}
+}
+
+func TestKoremutakeDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		word    string
+		want    uint64
+		wantErr bool
+	}{
+		{"upper-case and hyphens", "KO-re-MU-ta-ke", 10610353957, false},
+		{"whitespace", " ko re mu ta ke ", 10610353957, false},
+		{"three letter syllables", "stabry", 120*128 + 95, false},
+		{"empty", "", 0, true},
+		{"unknown syllable", "kowa", 0, true},
+		{"invalid character", "ko1", 0, true},
+		{"overflow", "betretretretretretretretretreba", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := KoremutakeDecode(tt.word)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidKoremutake) {
+					t.Errorf("KoremutakeDecode() expected ErrInvalidKoremutake, got: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("KoremutakeDecode() failed: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("KoremutakeDecode() failed, expected: %d, got: %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func FuzzKoremutakeEncode(f *testing.F) {
+	f.Add(uint64(0))
+	f.Add(uint64(10610353957))
+	f.Add(uint64(math.MaxUint64))
+	f.Fuzz(func(t *testing.T, number uint64) {
+		got, err := KoremutakeDecode(KoremutakeEncode(number))
+		if err != nil {
+			t.Fatalf("KoremutakeDecode() failed: %s", err)
+		}
+		if got != number {
+			t.Errorf("KoremutakeDecode() failed, expected: %d, got: %d", number, got)
+		}
+	})
+}
@@ -5,7 +5,9 @@
 package apg
 
 import (
+	"fmt"
 	"strings"
+	"unicode"
 )
 
 // Mode represents a mode of characters
@@ -28,6 +30,9 @@ const (
 	ModeSpecial
 	// ModeHumanReadable sets the bitmask to generate human readable passwords
 	ModeHumanReadable
+	// ModeDictation sets the bitmask to generate passwords that are safe to be read
+	// aloud (i. e. over the phone)
+	ModeDictation
 )
 
 const (
@@ -35,20 +40,69 @@ const (
 	CharRangeAlphaLower = "abcdefghijklmnopqrstuvwxyz"
 	// CharRangeAlphaLowerHuman represents the human-readable lower-case alphabetical characters
 	CharRangeAlphaLowerHuman = "abcdefghjkmnpqrstuvwxyz"
+	// CharRangeAlphaLowerDictation represents the lower-case alphabetical characters that
+	// do not sound alike when read aloud (without b/d/p/t/v/e/g, m/n and f/s)
+	CharRangeAlphaLowerDictation = "achijkloqruwxyz"
 	// CharRangeAlphaUpper represents all upper-case alphabetical characters
 	CharRangeAlphaUpper = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	// CharRangeAlphaUpperHuman represents the human-readable upper-case alphabetical characters
 	CharRangeAlphaUpperHuman = "ABCDEFGHJKMNPQRSTUVWXYZ"
+	// CharRangeAlphaUpperDictation represents the upper-case alphabetical characters that
+	// do not sound alike when read aloud (without B/D/P/T/V/E/G, M/N and F/S)
+	CharRangeAlphaUpperDictation = "ACHIJKLOQRUWXYZ"
 	// CharRangeNumeric represents all numerical characters
 	CharRangeNumeric = "1234567890"
 	// CharRangeNumericHuman represents all human-readable numerical characters
 	CharRangeNumericHuman = "23456789"
 	// CharRangeSpecial represents all special characters
-	CharRangeSpecial = `!\"#$%&'()*+,-./:;<=>?@[\\]^_{|}~`
+	CharRangeSpecial = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
 	// CharRangeSpecialHuman represents all human-readable special characters
 	CharRangeSpecialHuman = `#%*+-:;=`
+	// CharRangeSpecialDictation represents the special characters that have an unambiguous
+	// name when read aloud
+	CharRangeSpecialDictation = `#%*+=@`
 )
 
+// CharClass represents a custom, user-named class of characters that can be used
+// in addition to the built-in character modes
+type CharClass struct {
+	// Chars holds the characters of the class
+	Chars string
+	// MinAmount represents the minimum amount of characters of the class that have
+	// to be part of the generated password
+	MinAmount int64
+	// Name is the name of the class
+	Name string
+}
+
+// charModes is the list of character modes in the order they are used for
+// building the character range of a password
+var charModes = []Mode{ModeLowerCase, ModeNumeric, ModeSpecial, ModeUpperCase}
+
+// init validates the built-in character ranges. Every range needs to consist of
+// distinct, printable ASCII characters and every human-readable and dictation
+// range needs to be a subset of its corresponding full range
+func init() {
+	for _, mode := range charModes {
+		fullRange := fullCharRangeForMode(mode)
+		humanRange := humanCharRangeForMode(mode)
+		dictationRange := dictationCharRangeForMode(mode)
+		for _, charRange := range []string{fullRange, humanRange, dictationRange} {
+			if err := validateCharRange(charRange); err != nil {
+				panic(fmt.Sprintf("invalid built-in character range %q: %s", charRange, err))
+			}
+			if NewCharset(charRange).Len() != len(charRange) {
+				panic(fmt.Sprintf("built-in character range %q contains duplicate characters", charRange))
+			}
+		}
+		for _, char := range humanRange + dictationRange {
+			if !strings.ContainsRune(fullRange, char) {
+				panic(fmt.Sprintf("character %q is not part of the character range %q", char, fullRange))
+			}
+		}
+	}
+}
+
 // MaskSetMode sets a specific Mode to a given Mode bitmask
 func MaskSetMode(mask ModeMask, mode Mode) ModeMask { return ModeMask(uint8(mask) | uint8(mode)) }
 
@@ -61,6 +115,120 @@ func MaskToggleMode(mask ModeMask, mode Mode) ModeMask { return ModeMask(uint8(m
 // MaskHasMode returns true if a given Mode bitmask holds a specific Mode
 func MaskHasMode(mask ModeMask, mode Mode) bool { return uint8(mask)&uint8(mode) != 0 }
 
+// fullCharRangeForMode returns the range of all characters of the given character
+// Mode, regardless of the human-readable setting
+func fullCharRangeForMode(mode Mode) string {
+	switch mode {
+	case ModeLowerCase:
+		return CharRangeAlphaLower
+	case ModeNumeric:
+		return CharRangeNumeric
+	case ModeSpecial:
+		return CharRangeSpecial
+	case ModeUpperCase:
+		return CharRangeAlphaUpper
+	default:
+		return ""
+	}
+}
+
+// humanCharRangeForMode returns the range of human-readable characters of the
+// given character Mode
+func humanCharRangeForMode(mode Mode) string {
+	switch mode {
+	case ModeLowerCase:
+		return CharRangeAlphaLowerHuman
+	case ModeNumeric:
+		return CharRangeNumericHuman
+	case ModeSpecial:
+		return CharRangeSpecialHuman
+	case ModeUpperCase:
+		return CharRangeAlphaUpperHuman
+	default:
+		return ""
+	}
+}
+
+// dictationCharRangeForMode returns the range of characters of the given character
+// Mode that are safe to be read aloud
+func dictationCharRangeForMode(mode Mode) string {
+	switch mode {
+	case ModeLowerCase:
+		return CharRangeAlphaLowerDictation
+	case ModeNumeric:
+		// All digits are safe to be read aloud
+		return CharRangeNumeric
+	case ModeSpecial:
+		return CharRangeSpecialDictation
+	case ModeUpperCase:
+		return CharRangeAlphaUpperDictation
+	default:
+		return ""
+	}
+}
+
+// builtinCharRangeForMode returns the built-in range of characters of the given
+// character Mode, based on the human-readable and dictation settings of the given
+// Mode bitmask. In dictation mode only a single letter case is used: if lower-case
+// characters are enabled, the upper-case range is empty
+func builtinCharRangeForMode(mode Mode, mask ModeMask) string {
+	charRange := fullCharRangeForMode(mode)
+	if MaskHasMode(mask, ModeHumanReadable) {
+		charRange = humanCharRangeForMode(mode)
+	}
+	if MaskHasMode(mask, ModeDictation) {
+		if mode == ModeUpperCase && MaskHasMode(mask, ModeLowerCase) {
+			return ""
+		}
+		charRange = NewCharset(charRange).Intersect(NewCharset(dictationCharRangeForMode(mode))).String()
+	}
+	return charRange
+}
+
+// validateCharRange checks if the given character range is usable for password
+// generation. The range must not be empty and every character (grapheme cluster)
+// of the range must start with a printable, non-space base character and must not
+// contain any control characters
+func validateCharRange(charRange string) error {
+	if charRange == "" {
+		return ErrInvalidCharRange
+	}
+	for _, cluster := range graphemeClusters(charRange) {
+		for i, char := range cluster {
+			if (i == 0 && (!unicode.IsPrint(char) || unicode.IsSpace(char) || isGraphemeExtender(char))) ||
+				unicode.IsControl(char) {
+				return fmt.Errorf("%w: character %q is not printable", ErrInvalidCharRange, cluster)
+			}
+		}
+	}
+	return nil
+}
+
+// maskModeCount returns the amount of character modes that are set in the given
+// Mode bitmask
+func maskModeCount(mask ModeMask) int64 {
+	var count int64
+	for _, mode := range charModes {
+		if MaskHasMode(mask, mode) {
+			count++
+		}
+	}
+	return count
+}
+
+// maskEnsureModeCount makes sure that at least the given amount of character modes
+// are set in the given Mode bitmask. Missing modes are set in the order lower-case,
+// upper-case, numeric and special characters
+func maskEnsureModeCount(mask ModeMask, amount int64) ModeMask {
+	for _, mode := range []Mode{ModeLowerCase, ModeUpperCase, ModeNumeric, ModeSpecial} {
+		if maskModeCount(mask) >= amount {
+			break
+		}
+		mask = MaskSetMode(mask, mode)
+	}
+	return mask
+}
+
 func ModesFromFlags(maskString string) ModeMask {
 	cl := strings.Split(maskString, "")
 	var modeMask ModeMask
@@ -68,6 +236,10 @@ func ModesFromFlags(maskString string) ModeMask {
 		switch m {
 		case "C":
 			modeMask = MaskSetMode(modeMask, ModeLowerCase|ModeNumeric|ModeSpecial|ModeUpperCase)
+		case "d":
+			modeMask = MaskClearMode(modeMask, ModeDictation)
+		case "D":
+			modeMask = MaskSetMode(modeMask, ModeDictation)
 		case "h":
 			modeMask = MaskClearMode(modeMask, ModeHumanReadable)
 		case "H":
@@ -94,9 +266,47 @@ func ModesFromFlags(maskString string) ModeMask {
 	return modeMask
 }
 
+// ModeFlags are the valid characters of a character mode flags string. Upper-case
+// flags enable and lower-case flags disable the corresponding Mode
+const ModeFlags = "CDdHhLlNnSsUu"
+
+// MarshalText satisfies the encoding.TextMarshaler interface for the ModeMask type.
+// The bitmask is represented by the flags of its modes (i. e. "LNU"), as accepted
+// by ModesFromFlags
+func (m ModeMask) MarshalText() ([]byte, error) {
+	flags := make([]byte, 0, 6)
+	for _, mode := range []struct {
+		flag byte
+		mode Mode
+	}{
+		{'L', ModeLowerCase}, {'N', ModeNumeric}, {'S', ModeSpecial}, {'U', ModeUpperCase},
+		{'H', ModeHumanReadable}, {'D', ModeDictation},
+	} {
+		if MaskHasMode(m, mode.mode) {
+			flags = append(flags, mode.flag)
+		}
+	}
+	return flags, nil
+}
+
+// UnmarshalText satisfies the encoding.TextUnmarshaler interface for the ModeMask
+// type. The text is a character mode flags string as accepted by ModesFromFlags.
+// Unlike ModesFromFlags, unknown flags are returned as error
+func (m *ModeMask) UnmarshalText(text []byte) error {
+	for _, flag := range string(text) {
+		if !strings.ContainsRune(ModeFlags, flag) {
+			return fmt.Errorf("invalid character mode flag: %q", flag)
+		}
+	}
+	*m = ModesFromFlags(string(text))
+	return nil
+}
+
 // String satisfies the fmt.Stringer interface for the Mode type
 func (m Mode) String() string {
 	switch m {
+	case ModeDictation:
+		return "Dictation"
 	case ModeHumanReadable:
 		return "Human-readable"
 	case ModeLowerCase:
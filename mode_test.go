@@ -5,6 +5,8 @@
 package apg
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -53,6 +55,7 @@ func TestModesFromFlags(t *testing.T) {
 			ModeLowerCase, ModeNumeric, ModeSpecial,
 			ModeUpperCase,
 		}},
+		{"ModeDictation", "D", []Mode{ModeDictation}},
 		{"ModeHumanReadable", "H", []Mode{ModeHumanReadable}},
 		{"ModeLowerCase", "L", []Mode{ModeLowerCase}},
 		{"ModeNumeric", "N", []Mode{ModeNumeric}},
@@ -95,12 +98,56 @@ func TestModesFromFlags(t *testing.T) {
 	}
 }
 
+func TestModeMask_MarshalText(t *testing.T) {
+	tests := []struct {
+		mask ModeMask
+		want string
+	}{
+		{0, ""},
+		{DefaultMode, "LNU"},
+		{ModeLowerCase | ModeNumeric | ModeSpecial | ModeUpperCase | ModeHumanReadable, "LNSUH"},
+		{ModeLowerCase | ModeDictation, "LD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			text, err := tt.mask.MarshalText()
+			if err != nil {
+				t.Fatalf("ModeMask.MarshalText() failed: %s", err)
+			}
+			if string(text) != tt.want {
+				t.Errorf("ModeMask.MarshalText() failed, expected: %s, got: %s", tt.want, text)
+			}
+			var mask ModeMask
+			if err = mask.UnmarshalText(text); err != nil {
+				t.Fatalf("ModeMask.UnmarshalText() failed: %s", err)
+			}
+			if mask != tt.mask {
+				t.Errorf("ModeMask text round trip failed, expected: %d, got: %d", tt.mask, mask)
+			}
+		})
+	}
+}
+
+func TestModeMask_UnmarshalText(t *testing.T) {
+	var mask ModeMask
+	if err := mask.UnmarshalText([]byte("Cs")); err != nil {
+		t.Fatalf("ModeMask.UnmarshalText() failed: %s", err)
+	}
+	if want := ModeMask(ModeLowerCase | ModeNumeric | ModeUpperCase); mask != want {
+		t.Errorf("ModeMask.UnmarshalText() failed, expected: %d, got: %d", want, mask)
+	}
+	if err := mask.UnmarshalText([]byte("LUX")); err == nil {
+		t.Error("ModeMask.UnmarshalText() with invalid flag was supposed to fail")
+	}
+}
+
 func TestMode_String(t *testing.T) {
 	tt := []struct {
 		name string
 		m    Mode
 		e    string
 	}{
+		{"ModeDictation", ModeDictation, "Dictation"},
 		{"ModeHumanReadable", ModeHumanReadable, "Human-readable"},
 		{"ModeLowerCase", ModeLowerCase, "Lower-case"},
 		{"ModeNumeric", ModeNumeric, "Numeric"},
@@ -117,3 +164,73 @@ func TestMode_String(t *testing.T) {
 		})
 	}
 }
+
+func TestCharRangeSpecial(t *testing.T) {
+	if NewCharset(CharRangeSpecial).Len() != len(CharRangeSpecial) {
+		t.Errorf("CharRangeSpecial contains duplicate characters: %s", CharRangeSpecial)
+	}
+	if len(CharRangeSpecial) != 32 {
+		t.Errorf("CharRangeSpecial failed, expected: %d characters, got: %d", 32, len(CharRangeSpecial))
+	}
+	for _, char := range "`\\\"" {
+		if strings.Count(CharRangeSpecial, string(char)) != 1 {
+			t.Errorf("CharRangeSpecial failed, expected character %q exactly once", char)
+		}
+	}
+}
+
+func TestValidateCharRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		charRange string
+		wantErr   bool
+	}{
+		{"Valid range", "abc123!", false},
+		{"Empty range", "", true},
+		{"Space", "ab c", true},
+		{"Control character", "ab\tc", true},
+		{"Unicode letters", "abäßλ", false},
+		{"Emoji sequences", "👍🏽👨‍👩‍👧🇩🇪", false},
+		{"Combining mark", "e\u0301", false},
+		{"Leading combining mark", "\u0301e", true},
+		{"Zero-width space", "a\u200bb", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCharRange(tt.charRange)
+			if tt.wantErr && !errors.Is(err, ErrInvalidCharRange) {
+				t.Errorf("validateCharRange() failed, expected error: %s, got: %s", ErrInvalidCharRange, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateCharRange() failed: %s", err)
+			}
+		})
+	}
+}
+
+func TestBuiltinCharRangeForMode(t *testing.T) {
+	tests := []struct {
+		name string
+		mode Mode
+		mask ModeMask
+		want string
+	}{
+		{"Lower-case", ModeLowerCase, ModeLowerCase, CharRangeAlphaLower},
+		{"Lower-case human-readable", ModeLowerCase, ModeLowerCase | ModeHumanReadable, CharRangeAlphaLowerHuman},
+		{"Lower-case dictation", ModeLowerCase, ModeLowerCase | ModeDictation, CharRangeAlphaLowerDictation},
+		{
+			"Lower-case dictation and human-readable", ModeLowerCase,
+			ModeLowerCase | ModeDictation | ModeHumanReadable, "achjkqruwxyz",
+		},
+		{"Upper-case dictation", ModeUpperCase, ModeUpperCase | ModeDictation, CharRangeAlphaUpperDictation},
+		{"Upper-case dictation with lower-case", ModeUpperCase, ModeUpperCase | ModeLowerCase | ModeDictation, ""},
+		{"Special dictation", ModeSpecial, ModeSpecial | ModeDictation, CharRangeSpecialDictation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if charRange := builtinCharRangeForMode(tt.mode, tt.mask); charRange != tt.want {
+				t.Errorf("builtinCharRangeForMode() failed, expected: %s, got: %s", tt.want, charRange)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+const (
+	// passwordRulesSpecial represents the "special" character class of the passwordrules
+	// syntax (without the space character)
+	passwordRulesSpecial = "-~!@#$%^&*_+=`|(){}[:;\"'<>,.?]"
+	// passwordRulesASCIIPrintable represents the "ascii-printable" character class of the
+	// passwordrules syntax (without the space character)
+	passwordRulesASCIIPrintable = CharRangeAlphaLower + CharRangeAlphaUpper + CharRangeNumeric +
+		passwordRulesSpecial + `/\`
+)
+
+var (
+	// ErrInvalidPasswordRules is returned if a passwordrules string cannot be parsed
+	ErrInvalidPasswordRules = errors.New("invalid password rules")
+	// ErrPasswordRulesFailed is returned if a password does not pass the password rules
+	ErrPasswordRulesFailed = errors.New("password does not pass the password rules")
+)
+
+// PasswordRules represents a set of password rules in the "passwordrules" attribute
+// syntax, that is used by websites and password managers to describe their password
+// requirements, i. e.:
+//
+//	required: upper; required: digit; allowed: [-().&@?'#,/"+]; max-consecutive: 2; minlength: 20;
+//
+// See https://developer.apple.com/password-rules/ for details on the syntax.
+type PasswordRules struct {
+	// Allowed holds all characters that are allowed in the password
+	Allowed string
+	// AllowUnicode is set if any unicode character is allowed in the password
+	AllowUnicode bool
+	// MaxConsecutive represents the max-consecutive rule
+	MaxConsecutive int64
+	// MaxLength represents the maxlength rule
+	MaxLength int64
+	// MinLength represents the minlength rule
+	MinLength int64
+	// Required holds a list of character sets. The password needs to contain at least
+	// one character of each of the sets
+	Required []string
+}
+
+// ParsePasswordRules parses the given rules in the "passwordrules" attribute syntax
+// and returns the corresponding PasswordRules. HTML entities (i. e. &quot;) in the
+// rules are unescaped. Unknown properties are ignored, as recommended by the
+// specification.
+func ParsePasswordRules(rules string) (*PasswordRules, error) {
+	passwordRules := &PasswordRules{}
+	allowed := make(map[rune]struct{})
+	for _, rule := range splitOutsideBrackets(html.UnescapeString(rules), ';') {
+		name, value, found := strings.Cut(rule, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if name == "" && value == "" {
+			continue
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: missing value for property %q", ErrInvalidPasswordRules, name)
+		}
+
+		switch name {
+		case "required", "allowed":
+			chars, unicodeAllowed, err := parsePasswordRulesClasses(value)
+			if err != nil {
+				return nil, err
+			}
+			if unicodeAllowed {
+				passwordRules.AllowUnicode = true
+			}
+			for _, char := range chars {
+				allowed[char] = struct{}{}
+			}
+			if name == "required" && !unicodeAllowed {
+				passwordRules.Required = append(passwordRules.Required, chars)
+			}
+		case "max-consecutive", "minlength", "maxlength":
+			number, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || number < 0 {
+				return nil, fmt.Errorf("%w: invalid value %q for property %q", ErrInvalidPasswordRules,
+					value, name)
+			}
+			switch name {
+			case "max-consecutive":
+				passwordRules.MaxConsecutive = minNonZero(passwordRules.MaxConsecutive, number)
+			case "minlength":
+				passwordRules.MinLength = max(passwordRules.MinLength, number)
+			case "maxlength":
+				passwordRules.MaxLength = minNonZero(passwordRules.MaxLength, number)
+			}
+		}
+	}
+
+	// If no character class is given, any ASCII printable character is allowed
+	if len(allowed) == 0 {
+		passwordRules.Allowed = passwordRulesASCIIPrintable
+		return passwordRules, nil
+	}
+	for _, char := range passwordRulesASCIIPrintable {
+		if _, ok := allowed[char]; ok {
+			passwordRules.Allowed += string(char)
+		}
+	}
+	return passwordRules, nil
+}
+
+// WithPasswordRules configures the generator to only generate passwords that pass
+// the given PasswordRules. The rules are translated into the corresponding Config
+// values (character modes, excluded characters, minimum amounts, length and maximum
+// consecutive characters) and each generated password is additionally verified with
+// PasswordRules.Check.
+func WithPasswordRules(rules *PasswordRules) Option {
+	return func(config *Config) {
+		if rules == nil {
+			return
+		}
+		config.PasswordRules = rules
+
+		var mask ModeMask
+		exclude := config.ExcludeChars
+		minAmounts := map[Mode]*int64{
+			ModeLowerCase: &config.MinLowerCase,
+			ModeNumeric:   &config.MinNumeric,
+			ModeSpecial:   &config.MinSpecial,
+			ModeUpperCase: &config.MinUpperCase,
+		}
+		for _, mode := range charModes {
+			charRange := fullCharRangeForMode(mode)
+			allowedRange := ""
+			for _, char := range charRange {
+				if strings.ContainsRune(rules.Allowed, char) {
+					allowedRange += string(char)
+					continue
+				}
+				if !strings.ContainsRune(exclude, char) {
+					exclude += string(char)
+				}
+			}
+			if allowedRange == "" {
+				continue
+			}
+			mask = MaskSetMode(mask, mode)
+
+			// If a required set covers all allowed characters of a character mode
+			// we can use the minimum amount of this mode
+			for _, required := range rules.Required {
+				if sameChars(required, allowedRange) {
+					*minAmounts[mode] = max(*minAmounts[mode], 1)
+				}
+			}
+		}
+		for _, mode := range []Mode{ModeDictation, ModeHumanReadable} {
+			if MaskHasMode(config.Mode, mode) {
+				mask = MaskSetMode(mask, mode)
+			}
+		}
+		config.Mode = mask
+		config.ExcludeChars = exclude
+
+		if rules.MinLength > 0 {
+			config.MinLength = max(config.MinLength, rules.MinLength)
+			config.MaxLength = max(config.MaxLength, config.MinLength)
+		}
+		if rules.MaxLength > 0 {
+			config.MaxLength = min(config.MaxLength, rules.MaxLength)
+			config.MinLength = min(config.MinLength, config.MaxLength)
+		}
+		if config.FixedLength > 0 {
+			config.FixedLength = max(config.FixedLength, rules.MinLength)
+			if rules.MaxLength > 0 {
+				config.FixedLength = min(config.FixedLength, rules.MaxLength)
+			}
+		}
+		config.MaxRepeat = minNonZero(config.MaxRepeat, rules.MaxConsecutive)
+	}
+}
+
+// Check verifies the given password against the password rules. If the password
+// does not pass the rules, an error wrapping ErrPasswordRulesFailed and describing
+// the failed rule is returned.
+func (r *PasswordRules) Check(password string) error {
+	chars := []rune(password)
+	if r.MinLength > 0 && int64(len(chars)) < r.MinLength {
+		return fmt.Errorf("%w: the password is shorter than %d characters", ErrPasswordRulesFailed,
+			r.MinLength)
+	}
+	if r.MaxLength > 0 && int64(len(chars)) > r.MaxLength {
+		return fmt.Errorf("%w: the password is longer than %d characters", ErrPasswordRulesFailed,
+			r.MaxLength)
+	}
+	if r.MaxConsecutive > 0 && longestRepeat(chars) > r.MaxConsecutive {
+		return fmt.Errorf("%w: the password contains more than %d identical consecutive characters",
+			ErrPasswordRulesFailed, r.MaxConsecutive)
+	}
+	if !r.AllowUnicode {
+		for _, char := range chars {
+			if !strings.ContainsRune(r.Allowed, char) {
+				return fmt.Errorf("%w: the character %q is not allowed", ErrPasswordRulesFailed, char)
+			}
+		}
+	}
+	for _, required := range r.Required {
+		if !strings.ContainsAny(password, required) {
+			return fmt.Errorf("%w: the password requires one of the characters %q",
+				ErrPasswordRulesFailed, required)
+		}
+	}
+	return nil
+}
+
+// parsePasswordRulesClasses parses a comma-separated list of character classes of the
+// passwordrules syntax and returns all characters of these classes. If the "unicode"
+// class is part of the list, the second return value is true
+func parsePasswordRulesClasses(value string) (string, bool, error) {
+	var chars strings.Builder
+	unicodeAllowed := false
+	for _, class := range splitOutsideBrackets(value, ',') {
+		class = strings.TrimSpace(class)
+		switch strings.ToLower(class) {
+		case "upper":
+			chars.WriteString(CharRangeAlphaUpper)
+		case "lower":
+			chars.WriteString(CharRangeAlphaLower)
+		case "digit":
+			chars.WriteString(CharRangeNumeric)
+		case "special":
+			chars.WriteString(passwordRulesSpecial)
+		case "ascii-printable":
+			chars.WriteString(passwordRulesASCIIPrintable)
+		case "unicode":
+			unicodeAllowed = true
+		default:
+			if len(class) < 2 || class[0] != '[' || class[len(class)-1] != ']' {
+				return "", false, fmt.Errorf("%w: unknown character class %q", ErrInvalidPasswordRules, class)
+			}
+			for _, char := range class[1 : len(class)-1] {
+				if !strings.ContainsRune(passwordRulesASCIIPrintable, char) {
+					continue
+				}
+				chars.WriteRune(char)
+			}
+		}
+	}
+	return NewCharset(chars.String()).String(), unicodeAllowed, nil
+}
+
+// sameChars returns true if both given strings consist of the same set of characters
+func sameChars(a, b string) bool {
+	charsetA, charsetB := NewCharset(a), NewCharset(b)
+	return charsetA.Len() == charsetB.Len() && charsetA.Intersect(charsetB).Len() == charsetA.Len()
+}
+
+// splitOutsideBrackets splits the given string at each occurrence of the separator
+// that is not part of a custom character class in square brackets. A "]" directly
+// following the opening bracket is considered part of the custom character class.
+func splitOutsideBrackets(input string, separator rune) []string {
+	var parts []string
+	var current strings.Builder
+	inBrackets := false
+	bracketStart := false
+	for _, char := range input {
+		switch {
+		case !inBrackets && char == '[':
+			inBrackets, bracketStart = true, true
+			current.WriteRune(char)
+			continue
+		case inBrackets && char == ']' && !bracketStart:
+			inBrackets = false
+		case !inBrackets && char == separator:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		bracketStart = false
+		current.WriteRune(char)
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
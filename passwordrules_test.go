@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testPasswordRules = `required: upper; required: digit; allowed: [-().&@?'#,/&quot;+]; ` +
+	`max-consecutive: 2; minlength: 20;`
+
+func TestParsePasswordRules(t *testing.T) {
+	rules, err := ParsePasswordRules(testPasswordRules)
+	if err != nil {
+		t.Fatalf("ParsePasswordRules() failed: %s", err)
+	}
+	if rules.MinLength != 20 || rules.MaxLength != 0 || rules.MaxConsecutive != 2 {
+		t.Errorf("ParsePasswordRules() failed, unexpected length rules: %d/%d/%d", rules.MinLength,
+			rules.MaxLength, rules.MaxConsecutive)
+	}
+	if len(rules.Required) != 2 {
+		t.Fatalf("ParsePasswordRules() failed, expected 2 required sets, got: %d", len(rules.Required))
+	}
+	if !sameChars(rules.Required[0], CharRangeAlphaUpper) || !sameChars(rules.Required[1], CharRangeNumeric) {
+		t.Errorf("ParsePasswordRules() failed, unexpected required sets: %v", rules.Required)
+	}
+	if !sameChars(rules.Allowed, CharRangeAlphaUpper+CharRangeNumeric+`-().&@?'#,/"+`) {
+		t.Errorf("ParsePasswordRules() failed, unexpected allowed characters: %s", rules.Allowed)
+	}
+	if strings.ContainsAny(rules.Allowed, CharRangeAlphaLower) {
+		t.Errorf("ParsePasswordRules() failed, lower-case characters are not supposed to be allowed")
+	}
+}
+
+func TestParsePasswordRules_classes(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		allowed string
+	}{
+		{"Empty rules", "", passwordRulesASCIIPrintable},
+		{"Lower and special", "allowed: lower, special", CharRangeAlphaLower + passwordRulesSpecial},
+		{"Custom class with bracket", "allowed: digit, []-];", CharRangeNumeric + "]-"},
+		{"Custom class with separators", "required: [;,]; maxlength: 10", ";,"},
+		{"ASCII printable", "allowed: ascii-printable", passwordRulesASCIIPrintable},
+		{"Unknown property", "foo: bar; allowed: digit", CharRangeNumeric},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParsePasswordRules(tt.rules)
+			if err != nil {
+				t.Fatalf("ParsePasswordRules() failed: %s", err)
+			}
+			if !sameChars(rules.Allowed, tt.allowed) {
+				t.Errorf("ParsePasswordRules() failed, expected allowed: %s, got: %s", tt.allowed,
+					rules.Allowed)
+			}
+		})
+	}
+}
+
+func TestParsePasswordRules_fails(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{"Missing value", "required"},
+		{"Unknown class", "required: foo"},
+		{"Invalid number", "minlength: abc"},
+		{"Negative number", "maxlength: -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePasswordRules(tt.rules); !errors.Is(err, ErrInvalidPasswordRules) {
+				t.Errorf("ParsePasswordRules() expected ErrInvalidPasswordRules, got: %s", err)
+			}
+		})
+	}
+}
+
+func TestWithPasswordRules(t *testing.T) {
+	rules, err := ParsePasswordRules(testPasswordRules)
+	if err != nil {
+		t.Fatalf("ParsePasswordRules() failed: %s", err)
+	}
+	c := NewConfig(WithPasswordRules(rules))
+	if c.PasswordRules != rules {
+		t.Error("WithPasswordRules() failed, expected PasswordRules to be set")
+	}
+	if c.Mode != ModeUpperCase|ModeNumeric|ModeSpecial {
+		t.Errorf("WithPasswordRules() failed, unexpected mode mask: %d", c.Mode)
+	}
+	if c.MinUpperCase != 1 || c.MinNumeric != 1 || c.MinSpecial != 0 {
+		t.Errorf("WithPasswordRules() failed, unexpected minimum amounts: %d/%d/%d", c.MinUpperCase,
+			c.MinNumeric, c.MinSpecial)
+	}
+	if c.MinLength != 20 || c.MaxLength != 20 || c.MaxRepeat != 2 {
+		t.Errorf("WithPasswordRules() failed, unexpected length settings: %d/%d/%d", c.MinLength,
+			c.MaxLength, c.MaxRepeat)
+	}
+	g := New(c)
+	if strings.ContainsAny(g.GetCharRangeFromConfig(), `!$%*:;<=>[\]^_{|}~`) {
+		t.Errorf("WithPasswordRules() failed, character range contains not allowed characters: %s",
+			g.GetCharRangeFromConfig())
+	}
+
+	c = NewConfig(WithFixedLength(30), WithPasswordRules(&PasswordRules{
+		Allowed: CharRangeNumeric, MaxLength: 16,
+	}))
+	if c.FixedLength != 16 || c.MaxLength != 16 || c.MinLength != 12 {
+		t.Errorf("WithPasswordRules() failed, unexpected length settings: %d/%d/%d", c.FixedLength,
+			c.MinLength, c.MaxLength)
+	}
+	c = NewConfig(WithPasswordRules(nil))
+	if c.PasswordRules != nil {
+		t.Error("WithPasswordRules(nil) failed, expected PasswordRules to be nil")
+	}
+}
+
+func TestPasswordRules_Check(t *testing.T) {
+	rules, err := ParsePasswordRules("required: upper; required: digit, [#]; allowed: lower; " +
+		"max-consecutive: 2; minlength: 8; maxlength: 12")
+	if err != nil {
+		t.Fatalf("ParsePasswordRules() failed: %s", err)
+	}
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"Valid password", "abCdef1gh", false},
+		{"Valid with alternative", "abCdef#gh", false},
+		{"Too short", "abCd1", true},
+		{"Too long", "abCdef1ghijkl", true},
+		{"Max consecutive", "abCddd1gh", true},
+		{"Not allowed character", "abCde!1gh", true},
+		{"Missing required", "abcdef1gh", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rules.Check(tt.password)
+			if tt.wantErr && !errors.Is(err, ErrPasswordRulesFailed) {
+				t.Errorf("Check(%q) expected ErrPasswordRulesFailed, got: %s", tt.password, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Check(%q) failed: %s", tt.password, err)
+			}
+		})
+	}
+}
+
+func TestGenerateRandom_withPasswordRules(t *testing.T) {
+	rules, err := ParsePasswordRules("required: lower; required: [-.]; allowed: upper, digit; " +
+		"max-consecutive: 1; maxlength: 16")
+	if err != nil {
+		t.Fatalf("ParsePasswordRules() failed: %s", err)
+	}
+	g := New(NewConfig(WithAlgorithm(AlgoRandom), WithPasswordRules(rules)))
+	for range 100 {
+		pw, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if err = rules.Check(pw); err != nil {
+			t.Errorf("Generate() returned password that does not pass the rules: %s", err)
+		}
+	}
+}
+
+func TestGeneratePronounceable_withPasswordRules(t *testing.T) {
+	rules, err := ParsePasswordRules("required: lower; required: upper; required: digit; " +
+		"max-consecutive: 1; minlength: 12; maxlength: 16")
+	if err != nil {
+		t.Fatalf("ParsePasswordRules() failed: %s", err)
+	}
+	g := New(NewConfig(WithAlgorithm(AlgoPronounceable), WithPasswordRules(rules)))
+	for range 20 {
+		pw, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if err = rules.Check(pw); err != nil {
+			t.Errorf("Generate() returned password that does not pass the rules: %s", err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrInvalidProfile is returned if a profile is not valid
+	ErrInvalidProfile = errors.New("invalid profile")
+	// ErrUnknownProfile is returned if a profile name is not known
+	ErrUnknownProfile = errors.New("unknown profile")
+)
+
+// Profile represents a named password policy. A profile is a set of Settings that
+// configures the algorithm, the character modes and the constraints of the
+// generated passwords
+type Profile struct {
+	// Name is the name of the profile. It must not be empty and must not contain
+	// whitespace
+	Name string
+	// Description is a short, human-readable description of the profile
+	Description string
+	// Settings are the settings that are applied to a Config by the profile
+	Settings Settings
+}
+
+// profileEntry represents a profile in a profiles file
+type profileEntry struct {
+	Description string `toml:"description" json:"description"`
+	Settings
+}
+
+// builtinProfiles holds the built-in profiles in the order they are listed
+var builtinProfiles = []Profile{
+	{
+		Name:        "pin6",
+		Description: "6-digit numeric PIN",
+		Settings: Settings{
+			Algorithm:   settingValue(AlgoRandom),
+			Mode:        settingValue(ModeMask(ModeNumeric)),
+			FixedLength: settingValue[int64](6),
+		},
+	},
+	{
+		Name:        "wifi-wpa2",
+		Description: "WPA2 passphrase of human-readable characters that are easy to type on TVs and phones",
+		Settings: Settings{
+			Algorithm:   settingValue(AlgoRandom),
+			Mode:        settingValue(ModeMask(ModeLowerCase | ModeNumeric | ModeUpperCase | ModeHumanReadable)),
+			FixedLength: settingValue[int64](20),
+		},
+	},
+	{
+		Name:        "db-safe",
+		Description: "Database password without quotes, backticks or backslashes",
+		Settings: Settings{
+			Algorithm:    settingValue(AlgoRandom),
+			Mode:         settingValue(ModeMask(ModeLowerCase | ModeNumeric | ModeSpecial | ModeUpperCase)),
+			FixedLength:  settingValue[int64](24),
+			ExcludeChars: settingValue("\"'`\\"),
+		},
+	},
+	{
+		Name:        "ad-complex",
+		Description: "Active Directory complexity requirements (3 of 5 character categories)",
+		Settings: Settings{
+			ADComplexity: settingValue(true),
+			Algorithm:    settingValue(AlgoRandom),
+			Mode:         settingValue(ModeMask(ModeLowerCase | ModeNumeric | ModeSpecial | ModeUpperCase)),
+			MinLength:    settingValue[int64](14),
+			MaxLength:    settingValue[int64](20),
+		},
+	},
+	{
+		Name:        "legacy8",
+		Description: "8 alphanumeric characters of all letter cases and digits for legacy systems",
+		Settings: Settings{
+			Algorithm:   settingValue(AlgoRandom),
+			Mode:        settingValue(ModeMask(ModeLowerCase | ModeNumeric | ModeUpperCase)),
+			FixedLength: settingValue[int64](8),
+			MinClasses:  settingValue[int64](3),
+		},
+	},
+	{
+		Name:        "urlsafe",
+		Description: "Only unreserved URL characters that need no percent-encoding",
+		Settings: Settings{
+			Algorithm:   settingValue(AlgoRandom),
+			Mode:        settingValue(ModeMask(ModeLowerCase | ModeNumeric | ModeSpecial | ModeUpperCase)),
+			FixedLength: settingValue[int64](32),
+			SafeFor:     settingValue(EscapeURL.String()),
+		},
+	},
+	{
+		Name: "nist-800-63b",
+		Description: "NIST SP 800-63B memorized secret: at least 15 characters, no composition rules, " +
+			"checked against the HIBP database",
+		Settings: Settings{
+			Algorithm: settingValue(AlgoRandom),
+			Mode:      settingValue(ModeMask(ModeLowerCase | ModeNumeric | ModeSpecial | ModeUpperCase)),
+			MinLength: settingValue[int64](15),
+			MaxLength: settingValue[int64](20),
+			CheckHIBP: settingValue(true),
+		},
+	},
+}
+
+// init validates the built-in profiles
+func init() {
+	for _, profile := range builtinProfiles {
+		if err := profile.Validate(); err != nil {
+			panic(fmt.Sprintf("invalid built-in profile %q: %s", profile.Name, err))
+		}
+	}
+}
+
+// BuiltinProfiles returns the built-in profiles
+func BuiltinProfiles() []Profile {
+	return append([]Profile{}, builtinProfiles...)
+}
+
+// ProfileByName returns the profile with the given name from the given profiles,
+// or from the built-in profiles if no profiles are given. Profile names are
+// case-insensitive
+func ProfileByName(name string, profiles ...Profile) (Profile, error) {
+	if len(profiles) == 0 {
+		profiles = builtinProfiles
+	}
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, strings.TrimSpace(name)) {
+			return profile, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+}
+
+// LoadProfiles reads the user-defined profiles from the file at the given path.
+// Every profile is a table (TOML) or object (JSON) in the "profiles" table/object,
+// named after the profile. It holds an optional description and the keys of the
+// Settings. Files with a ".json" extension are read as JSON, any other file is
+// read as TOML. The profiles are returned sorted by name
+func LoadProfiles(path string) ([]Profile, error) {
+	var file struct {
+		Profiles map[string]profileEntry `toml:"profiles" json:"profiles"`
+	}
+	if err := decodeSettingsFile(path, &file); err != nil {
+		return nil, err
+	}
+
+	profiles := make([]Profile, 0, len(file.Profiles))
+	for name, entry := range file.Profiles {
+		profile := Profile{Name: name, Description: entry.Description, Settings: entry.Settings}
+		if err := profile.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// Apply applies the settings of the profile to the given Config
+func (p Profile) Apply(config *Config) error {
+	if err := p.Settings.Apply(config); err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidProfile, p.Name, err)
+	}
+	return nil
+}
+
+// Validate checks if the profile has a valid name and if its settings can be
+// applied to a Config
+func (p Profile) Validate() error {
+	if p.Name == "" || strings.IndexFunc(p.Name, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("%w: name %q must not be empty or contain whitespace", ErrInvalidProfile, p.Name)
+	}
+	return p.Apply(NewConfig())
+}
+
+// settingValue returns a pointer to the given value, for the optional fields of
+// the Settings
+func settingValue[T any](value T) *T {
+	return &value
+}
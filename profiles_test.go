@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testProfilesTOML = `[profiles.team-db]
+description = "Team database passwords"
+mode = "LUNS"
+fixed_length = 32
+exclude_chars = '"\;'
+
+[profiles.kiosk]
+algorithm = "pronounceable"
+syllable_separator = "-"
+`
+
+func TestBuiltinProfiles(t *testing.T) {
+	names := []string{"pin6", "wifi-wpa2", "db-safe", "ad-complex", "legacy8", "urlsafe", "nist-800-63b"}
+	profiles := BuiltinProfiles()
+	if len(profiles) != len(names) {
+		t.Fatalf("BuiltinProfiles() failed, expected %d profiles, got: %d", len(names), len(profiles))
+	}
+	for i, name := range names {
+		if profiles[i].Name != name {
+			t.Errorf("BuiltinProfiles() failed, expected: %s, got: %s", name, profiles[i].Name)
+		}
+		if profiles[i].Description == "" {
+			t.Errorf("BuiltinProfiles() failed, profile %s has no description", name)
+		}
+	}
+}
+
+func TestBuiltinProfiles_generate(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(password string) bool
+	}{
+		{"pin6", func(password string) bool {
+			return len(password) == 6 && strings.Trim(password, CharRangeNumeric) == ""
+		}},
+		{"wifi-wpa2", func(password string) bool { return len(password) == 20 }},
+		{"db-safe", func(password string) bool {
+			return len(password) == 24 && !strings.ContainsAny(password, "\"'`\\")
+		}},
+		{"ad-complex", func(password string) bool { return len(password) >= 14 && len(password) <= 20 }},
+		{"legacy8", func(password string) bool {
+			return len(password) == 8 && strings.ContainsAny(password, CharRangeNumeric) &&
+				strings.ContainsAny(password, CharRangeAlphaLower) &&
+				strings.ContainsAny(password, CharRangeAlphaUpper)
+		}},
+		{"urlsafe", func(password string) bool {
+			escaped, err := Escape(password, EscapeURL)
+			return len(password) == 32 && err == nil && escaped == password
+		}},
+		{"nist-800-63b", func(password string) bool { return len(password) >= 15 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := ProfileByName(tt.name)
+			if err != nil {
+				t.Fatalf("ProfileByName() failed: %s", err)
+			}
+			config := NewConfig()
+			if err = profile.Apply(config); err != nil {
+				t.Fatalf("Profile.Apply() failed: %s", err)
+			}
+			generator := New(config)
+			for range 20 {
+				password, err := generator.Generate()
+				if err != nil {
+					t.Fatalf("Generate() failed: %s", err)
+				}
+				if !tt.check(password) {
+					t.Errorf("Generate() with profile %s returned an invalid password: %s", tt.name, password)
+				}
+			}
+		})
+	}
+}
+
+func TestBuiltinProfiles_adComplex(t *testing.T) {
+	profile, err := ProfileByName("ad-complex")
+	if err != nil {
+		t.Fatalf("ProfileByName() failed: %s", err)
+	}
+	config := NewConfig()
+	if err = profile.Apply(config); err != nil {
+		t.Fatalf("Profile.Apply() failed: %s", err)
+	}
+	want := NewConfig(WithModeMask(*profile.Settings.Mode), WithADComplexity("", ""))
+	if config.MinClasses != want.MinClasses || config.Mode != want.Mode {
+		t.Errorf("Profile.Apply() failed, expected min classes %d and mode %d, got: %d and %d",
+			want.MinClasses, want.Mode, config.MinClasses, config.Mode)
+	}
+}
+
+func TestProfileByName(t *testing.T) {
+	profile, err := ProfileByName(" Pin6 ")
+	if err != nil {
+		t.Fatalf("ProfileByName() failed: %s", err)
+	}
+	if profile.Name != "pin6" {
+		t.Errorf("ProfileByName() failed, expected: %s, got: %s", "pin6", profile.Name)
+	}
+	if _, err = ProfileByName("pin7"); !errors.Is(err, ErrUnknownProfile) {
+		t.Errorf("ProfileByName() expected ErrUnknownProfile, got: %v", err)
+	}
+
+	custom := Profile{Name: "pin6", Description: "custom"}
+	profile, err = ProfileByName("pin6", append([]Profile{custom}, BuiltinProfiles()...)...)
+	if err != nil {
+		t.Fatalf("ProfileByName() failed: %s", err)
+	}
+	if profile.Description != "custom" {
+		t.Errorf("ProfileByName() failed, expected the first matching profile, got: %s", profile.Description)
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.toml")
+	if err := os.WriteFile(path, []byte(testProfilesTOML), 0o600); err != nil {
+		t.Fatalf("failed to write profiles file: %s", err)
+	}
+	profiles, err := LoadProfiles(path)
+	if err != nil {
+		t.Fatalf("LoadProfiles() failed: %s", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "kiosk" || profiles[1].Name != "team-db" {
+		t.Fatalf("LoadProfiles() failed, expected profiles kiosk and team-db, got: %v", profiles)
+	}
+	if profiles[1].Description != "Team database passwords" {
+		t.Errorf("LoadProfiles() failed, expected description: %s, got: %s", "Team database passwords",
+			profiles[1].Description)
+	}
+	config := NewConfig()
+	if err = profiles[1].Apply(config); err != nil {
+		t.Fatalf("Profile.Apply() failed: %s", err)
+	}
+	if config.FixedLength != 32 || config.ExcludeChars != `"\;` {
+		t.Errorf("LoadProfiles() failed, expected length 32 and excluded chars %s, got: %d and %s",
+			`"\;`, config.FixedLength, config.ExcludeChars)
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "profiles.json")
+	if err = os.WriteFile(jsonPath, []byte(`{"profiles": {"short": {"fixed_length": 8}}}`), 0o600); err != nil {
+		t.Fatalf("failed to write profiles file: %s", err)
+	}
+	if profiles, err = LoadProfiles(jsonPath); err != nil || len(profiles) != 1 {
+		t.Errorf("LoadProfiles() failed, expected 1 profile, got: %d (%v)", len(profiles), err)
+	}
+}
+
+func TestLoadProfiles_fails(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{"unknown key", "[profiles.team]\nmin_lenght = 12\n", ErrUnknownSetting},
+		{"invalid setting", "[profiles.team]\nsafe_for = \"csv\"\n", ErrInvalidProfile},
+		{"invalid name", "[profiles.\"my team\"]\nmin_length = 12\n", ErrInvalidProfile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profiles.toml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write profiles file: %s", err)
+			}
+			if _, err := LoadProfiles(path); !errors.Is(err, tt.wantErr) {
+				t.Errorf("LoadProfiles() failed, expected error: %s, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,449 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LengthStrategy represents the strategy that is used to meet the requested password
+// length in pronounceable password mode
+type LengthStrategy uint8
+
+const (
+	// LengthStrategyMinimum adds syllables until the password consists of at least
+	// the requested amount of characters. The password might exceed the requested
+	// length by up to one syllable
+	LengthStrategyMinimum LengthStrategy = iota
+	// LengthStrategyExact selects syllables whose lengths sum up to exactly the
+	// requested amount of characters
+	LengthStrategyExact
+	// LengthStrategySyllables interprets the requested length as the amount of
+	// syllables of the password
+	LengthStrategySyllables
+)
+
+// CapitalizationStyle represents the style that is used to convert characters of
+// the syllables of pronounceable passwords to upper-case
+type CapitalizationStyle uint8
+
+const (
+	// CapitalizationRandomLetter performs a coinflip for each syllable and converts
+	// a random letter of the syllable to upper-case
+	CapitalizationRandomLetter CapitalizationStyle = iota
+	// CapitalizationNone does not convert any letters to upper-case
+	CapitalizationNone
+	// CapitalizationFirstSyllable converts the first letter of the first syllable
+	// to upper-case
+	CapitalizationFirstSyllable
+	// CapitalizationRandomSyllable converts the first letter of a randomly selected
+	// syllable to upper-case
+	CapitalizationRandomSyllable
+)
+
+var (
+	// ErrUnknownCapitalizationStyle is returned if a capitalization style name is
+	// not known
+	ErrUnknownCapitalizationStyle = errors.New("unknown capitalization style")
+	// ErrUnknownLengthStrategy is returned if a length strategy name is not known
+	ErrUnknownLengthStrategy = errors.New("unknown length strategy")
+)
+
+// leetspeakChars maps the letters that can be substituted in leetspeak to their
+// numeric substitutes
+var leetspeakChars = map[rune]rune{
+	'a': '4', 'b': '8', 'e': '3', 'g': '9', 'i': '1', 'o': '0', 's': '5', 't': '7',
+}
+
+// CapitalizationStyleFromString returns the CapitalizationStyle for the given name.
+// Valid names are "random-letter" (or "letter"), "none", "first-syllable" (or
+// "first") and "random-syllable" (or "syllable")
+func CapitalizationStyleFromString(style string) (CapitalizationStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(style)) {
+	case "random-letter", "letter":
+		return CapitalizationRandomLetter, nil
+	case "none":
+		return CapitalizationNone, nil
+	case "first-syllable", "first":
+		return CapitalizationFirstSyllable, nil
+	case "random-syllable", "syllable":
+		return CapitalizationRandomSyllable, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCapitalizationStyle, style)
+	}
+}
+
+// String satisfies the fmt.Stringer interface for the CapitalizationStyle type
+func (s CapitalizationStyle) String() string {
+	switch s {
+	case CapitalizationRandomLetter:
+		return "random-letter"
+	case CapitalizationNone:
+		return "none"
+	case CapitalizationFirstSyllable:
+		return "first-syllable"
+	case CapitalizationRandomSyllable:
+		return "random-syllable"
+	default:
+		return "unknown"
+	}
+}
+
+// LengthStrategyFromString returns the LengthStrategy for the given name. Valid
+// names are "minimum" (or "min"), "exact" and "syllables" (or "syl")
+func LengthStrategyFromString(strategy string) (LengthStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(strategy)) {
+	case "minimum", "min":
+		return LengthStrategyMinimum, nil
+	case "exact":
+		return LengthStrategyExact, nil
+	case "syllables", "syl":
+		return LengthStrategySyllables, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownLengthStrategy, strategy)
+	}
+}
+
+// String satisfies the fmt.Stringer interface for the LengthStrategy type
+func (s LengthStrategy) String() string {
+	switch s {
+	case LengthStrategyMinimum:
+		return "minimum"
+	case LengthStrategyExact:
+		return "exact"
+	case LengthStrategySyllables:
+		return "syllables"
+	default:
+		return "unknown"
+	}
+}
+
+// pronounceablePool returns the pool of syllables and characters that are used
+// for the generation of pronounceable passwords. The syllables of the configured
+// SyllableSet are part of the pool if lower-case or upper-case characters are
+// enabled in the Mode of the generator's configuration. If only upper-case
+// characters are enabled, the syllables are converted to upper-case. Numeric and
+// special characters (from the human-readable ranges, unless custom ranges are
+// configured) are added if enabled. Syllables and characters that contain excluded
+// or confusable characters are not part of the pool
+func (g *Generator) pronounceablePool() []string {
+	isExcludedChar := g.isExcludedFunc()
+	isExcluded := func(item string) bool {
+		for _, char := range item {
+			if isExcludedChar(char) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var pool []string
+	lowerCase := MaskHasMode(g.config.Mode, ModeLowerCase)
+	upperCase := MaskHasMode(g.config.Mode, ModeUpperCase)
+	if lowerCase || upperCase {
+		for _, syllable := range g.syllableSet().Syllables {
+			if !lowerCase {
+				syllable = strings.ToUpper(syllable)
+			}
+			if !isExcluded(syllable) {
+				pool = append(pool, syllable)
+			}
+		}
+	}
+	for _, mode := range []Mode{ModeNumeric, ModeSpecial} {
+		if !MaskHasMode(g.config.Mode, mode) {
+			continue
+		}
+		for _, char := range g.pronounceableCharRange(mode) {
+			if !isExcludedChar(char) {
+				pool = append(pool, string(char))
+			}
+		}
+	}
+	return pool
+}
+
+// pronounceableCharRange returns the range of characters of the given character
+// Mode that is added to the pool of pronounceable passwords. If a custom character
+// range is configured for the Mode, it is used. Otherwise the human-readable range
+// of the Mode is used
+func (g *Generator) pronounceableCharRange(mode Mode) string {
+	if charRange, ok := g.config.CharRanges[mode]; ok {
+		return NewCharset(charRange).String()
+	}
+	return builtinCharRangeForMode(mode, MaskSetMode(g.config.Mode, ModeHumanReadable))
+}
+
+// capitalizablePositions returns the positions of the characters of the given
+// syllable that can be converted to upper-case. Characters can only be converted if
+// both lower-case and upper-case characters are enabled and the upper-case variant
+// of the character is neither excluded nor confusable
+func (g *Generator) capitalizablePositions(syllable string) []int {
+	if !MaskHasMode(g.config.Mode, ModeLowerCase) || !MaskHasMode(g.config.Mode, ModeUpperCase) {
+		return nil
+	}
+	isExcluded := g.isExcludedFunc()
+	var positions []int
+	seen := make(map[rune]struct{})
+	for i, char := range []rune(syllable) {
+		// All occurrences of a character are converted at once, so only the first
+		// occurrence is a distinct position
+		if _, ok := seen[char]; ok {
+			continue
+		}
+		seen[char] = struct{}{}
+		upper := unicode.ToUpper(char)
+		if upper == char || isExcluded(upper) {
+			continue
+		}
+		positions = append(positions, i)
+	}
+	return positions
+}
+
+// validatePronounceable checks if the configured SyllableSet is valid and if the
+// configured minimum amounts of characters per character Mode can be met by the
+// given pool of pronounceable syllables and characters
+func (g *Generator) validatePronounceable(length int64, pool []string) error {
+	if err := g.syllableSet().Validate(); err != nil {
+		return err
+	}
+	if len(pool) == 0 {
+		return ErrUnsatisfiableConstraints
+	}
+	var minSum int64
+	for _, mode := range charModes {
+		minAmount, _ := g.amountsForMode(mode)
+		if minAmount <= 0 {
+			continue
+		}
+		minSum += minAmount
+		available := false
+		for _, item := range pool {
+			for _, char := range item {
+				if g.modeOfChar(char) == mode ||
+					(mode == ModeUpperCase && len(g.capitalizablePositions(item)) > 0) {
+					available = true
+				}
+			}
+		}
+		if !available {
+			return ErrUnsatisfiableConstraints
+		}
+	}
+	if minSum > length && g.config.LengthStrategy != LengthStrategySyllables {
+		return ErrUnsatisfiableConstraints
+	}
+	// Only the random letter style converts more than one letter to upper-case
+	if MaskHasMode(g.config.Mode, ModeLowerCase) && g.config.MinUpperCase > 0 &&
+		(g.config.Capitalization == CapitalizationNone ||
+			(g.config.Capitalization != CapitalizationRandomLetter && g.config.MinUpperCase > 1)) {
+		return ErrUnsatisfiableConstraints
+	}
+	return nil
+}
+
+// styleSyllables applies the configured CapitalizationStyle and the leetspeak
+// substitution to the given syllables and returns the styled syllables
+func (g *Generator) styleSyllables(items []string) ([]string, error) {
+	styled := make([]string, len(items))
+	copy(styled, items)
+
+	var capitalize []int
+	switch g.config.Capitalization {
+	case CapitalizationRandomLetter:
+		for i, item := range styled {
+			if len(g.capitalizablePositions(item)) > 0 && g.CoinFlipBool() {
+				capitalize = append(capitalize, i)
+			}
+		}
+	case CapitalizationFirstSyllable, CapitalizationRandomSyllable:
+		var candidates []int
+		for i, item := range styled {
+			if len(g.capitalizablePositions(item)) > 0 {
+				candidates = append(candidates, i)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		if g.config.Capitalization == CapitalizationFirstSyllable {
+			capitalize = append(capitalize, candidates[0])
+			break
+		}
+		randNum, err := g.RandNum(int64(len(candidates)))
+		if err != nil {
+			return nil, err
+		}
+		capitalize = append(capitalize, candidates[randNum])
+	}
+	for _, i := range capitalize {
+		positions := g.capitalizablePositions(styled[i])
+		position := positions[0]
+		if g.config.Capitalization == CapitalizationRandomLetter {
+			randNum, err := g.RandNum(int64(len(positions)))
+			if err != nil {
+				return nil, err
+			}
+			position = positions[randNum]
+		}
+		char := string([]rune(styled[i])[position])
+		styled[i] = strings.ReplaceAll(styled[i], char, strings.ToUpper(char))
+	}
+
+	if g.config.Leetspeak {
+		for i, item := range styled {
+			chars := []rune(item)
+			for _, position := range g.leetspeakPositions(item) {
+				if g.CoinFlipBool() {
+					chars[position] = leetspeakChars[unicode.ToLower(chars[position])]
+				}
+			}
+			styled[i] = string(chars)
+		}
+	}
+	return styled, nil
+}
+
+// leetspeakPositions returns the positions of the characters of the given syllable
+// that can be substituted in leetspeak. Characters can only be substituted if their
+// numeric substitute is part of the enabled numeric characters and neither excluded
+// nor confusable
+func (g *Generator) leetspeakPositions(syllable string) []int {
+	if !MaskHasMode(g.config.Mode, ModeNumeric) {
+		return nil
+	}
+	isExcluded := g.isExcludedFunc()
+	allowed := NewCharset(g.pronounceableCharRange(ModeNumeric)).filter(func(char rune) bool {
+		return !isExcluded(char)
+	})
+	var positions []int
+	for i, char := range []rune(syllable) {
+		substitute, ok := leetspeakChars[unicode.ToLower(char)]
+		if ok && allowed.Contains(substitute) {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// separatorLength returns the amount of characters of the configured syllable
+// separator
+func (g *Generator) separatorLength() int64 {
+	return int64(utf8.RuneCountInString(g.config.SyllableSeparator))
+}
+
+// pronounceableItems selects the syllables and characters of a pronounceable password
+// of the given length from the given pool, based on the configured LengthStrategy.
+// The length includes the configured syllable separators. For LengthStrategyExact, the
+// given weights need to be the result of exactLengthWeights for the pool
+func (g *Generator) pronounceableItems(length int64, pool []string, weights []*big.Int) ([]string, error) {
+	var items []string
+	switch g.config.LengthStrategy {
+	case LengthStrategyExact:
+		// Each syllable is selected with the probability that a randomly selected
+		// syllable is followed by syllables that exactly fill the remaining length
+		poolSize := big.NewInt(int64(len(pool)))
+		separatorLength := g.separatorLength()
+		for remaining := length + separatorLength; remaining > 0; {
+			randNum, err := rand.Int(rand.Reader, weights[remaining])
+			if err != nil {
+				return nil, fmt.Errorf("random number generation failed: %w", err)
+			}
+			for _, item := range pool {
+				itemLength := int64(utf8.RuneCountInString(item)) + separatorLength
+				if itemLength > remaining {
+					continue
+				}
+				weight := new(big.Int).Exp(poolSize, big.NewInt(itemLength-1), nil)
+				weight.Mul(weight, weights[remaining-itemLength])
+				if randNum.Cmp(weight) < 0 {
+					items = append(items, item)
+					remaining -= itemLength
+					break
+				}
+				randNum.Sub(randNum, weight)
+			}
+		}
+	case LengthStrategySyllables:
+		for int64(len(items)) < length {
+			randNum, err := g.RandNum(int64(len(pool)))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, pool[randNum])
+		}
+	default:
+		var itemsLength int64
+		for itemsLength < length {
+			randNum, err := g.RandNum(int64(len(pool)))
+			if err != nil {
+				return nil, err
+			}
+			if len(items) > 0 {
+				itemsLength += g.separatorLength()
+			}
+			items = append(items, pool[randNum])
+			itemsLength += int64(utf8.RuneCountInString(pool[randNum]))
+		}
+	}
+	return items, nil
+}
+
+// exactLengthWeights returns, for every length from 0 up to the given length, the
+// probability that randomly selected syllables and characters of the given pool
+// exactly fill the length, multiplied by the size of the pool to the power of the
+// length. This way the weights are integers and a combination of k syllables has a
+// weight of the size of the pool to the power of the length minus k. Every syllable
+// is followed by a separator of the given length, so the length needs to include
+// one additional separator
+func exactLengthWeights(length int64, pool []string, separatorLength int64) []*big.Int {
+	poolSize := big.NewInt(int64(len(pool)))
+	weights := make([]*big.Int, length+1)
+	weights[0] = big.NewInt(1)
+	for current := int64(1); current <= length; current++ {
+		weights[current] = new(big.Int)
+		for _, item := range pool {
+			itemLength := int64(utf8.RuneCountInString(item)) + separatorLength
+			if itemLength == 0 || itemLength > current {
+				continue
+			}
+			weight := new(big.Int).Exp(poolSize, big.NewInt(itemLength-1), nil)
+			weights[current].Add(weights[current], weight.Mul(weight, weights[current-itemLength]))
+		}
+	}
+	return weights
+}
+
+// exactLengthItems returns the expected amount of syllables and characters of a
+// password with the given length for LengthStrategyExact, based on the given
+// weights of exactLengthWeights with the same separator length
+func exactLengthItems(length int64, pool []string, separatorLength int64, weights []*big.Int) float64 {
+	poolSize := big.NewInt(int64(len(pool)))
+	expected := make([]float64, length+1)
+	for current := int64(1); current <= length; current++ {
+		if weights[current].Sign() == 0 {
+			continue
+		}
+		total := new(big.Float).SetInt(weights[current])
+		for _, item := range pool {
+			itemLength := int64(utf8.RuneCountInString(item)) + separatorLength
+			if itemLength == 0 || itemLength > current || weights[current-itemLength].Sign() == 0 {
+				continue
+			}
+			weight := new(big.Int).Exp(poolSize, big.NewInt(itemLength-1), nil)
+			weight.Mul(weight, weights[current-itemLength])
+			probability, _ := new(big.Float).Quo(new(big.Float).SetInt(weight), total).Float64()
+			expected[current] += probability * (1 + expected[current-itemLength])
+		}
+	}
+	return expected[length]
+}
@@ -0,0 +1,418 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGenerator_pronounceablePool(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        ModeMask
+		exclude     string
+		wantChars   string
+		unwantChars string
+	}{
+		{"default mode", DefaultMode, "", CharRangeNumericHuman, CharRangeSpecialHuman},
+		{"with special", DefaultMode | ModeSpecial, "", CharRangeNumericHuman + CharRangeSpecialHuman, ""},
+		{"lower-case only", ModeLowerCase, "", "", CharRangeNumeric + CharRangeSpecial + CharRangeAlphaUpper},
+		{"upper-case only", ModeUpperCase, "", "", CharRangeNumeric + CharRangeSpecial + CharRangeAlphaLower},
+		{"excluded chars", DefaultMode | ModeSpecial, "a2#", "", "a2#"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := New(NewConfig(WithModeMask(tt.mode), WithExcludeChars(tt.exclude)))
+			pool := strings.Join(generator.pronounceablePool(), "")
+			for _, char := range tt.wantChars {
+				if !strings.ContainsRune(pool, char) {
+					t.Errorf("pronounceablePool() failed, expected character %q to be part of the pool", char)
+				}
+			}
+			if strings.ContainsAny(pool, tt.unwantChars) {
+				t.Errorf("pronounceablePool() failed, expected none of %q to be part of the pool", tt.unwantChars)
+			}
+		})
+	}
+}
+
+func TestGenerator_capitalizablePositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     ModeMask
+		exclude  string
+		syllable string
+		want     int
+	}{
+		{"lower and upper", DefaultMode, "", "tro", 3},
+		{"repeated char", DefaultMode, "", "eve", 2},
+		{"lower only", ModeLowerCase, "", "tro", 0},
+		{"excluded upper", DefaultMode, "TR", "tro", 1},
+		{"digit", DefaultMode, "", "7", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := New(NewConfig(WithModeMask(tt.mode), WithExcludeChars(tt.exclude)))
+			if got := len(generator.capitalizablePositions(tt.syllable)); got != tt.want {
+				t.Errorf("capitalizablePositions() failed, expected: %d, got: %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGeneratePronounceable_modeAndExclusions(t *testing.T) {
+	config := NewConfig(WithModeMask(ModeLowerCase|ModeUpperCase), WithExcludeChars("aAeE"))
+	generator := New(config)
+	for range 50 {
+		password, err := generator.generatePronounceable()
+		if err != nil {
+			t.Fatalf("generatePronounceable() failed: %s", err)
+		}
+		if strings.ContainsAny(password, "aAeE"+CharRangeNumeric+CharRangeSpecial) {
+			t.Errorf("generatePronounceable() failed, password %q contains excluded characters", password)
+		}
+	}
+}
+
+func TestGeneratePronounceable_minimums(t *testing.T) {
+	config := NewConfig(WithModeMask(DefaultMode|ModeSpecial), WithMinNumeric(2), WithMinSpecial(1),
+		WithMinUppercase(2))
+	generator := New(config)
+	for range 20 {
+		password, err := generator.generatePronounceable()
+		if err != nil {
+			t.Fatalf("generatePronounceable() failed: %s", err)
+		}
+		if !generator.checkMinimumRequirements(password) {
+			t.Errorf("generatePronounceable() failed, password %q does not meet the minimum requirements",
+				password)
+		}
+	}
+}
+
+func TestGeneratePronounceable_constraints(t *testing.T) {
+	config := NewConfig(WithModeMask(DefaultMode|ModeSpecial), WithFirstCharMode(ModeUpperCase),
+		WithLastCharMode(ModeLowerCase|ModeNumeric), WithMaxRepeat(1), WithMaxClassRepeat(3), WithMaxSequence(2),
+		WithMaxNumeric(3))
+	generator := New(config)
+	for range 20 {
+		password, err := generator.generatePronounceable()
+		if err != nil {
+			t.Fatalf("generatePronounceable() failed: %s", err)
+		}
+		if !generator.checkConstraints(password) {
+			t.Errorf("generatePronounceable() failed, password %q does not meet the constraints", password)
+		}
+	}
+}
+
+func TestGeneratePronounceable_unsatisfiable(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{"special not enabled", NewConfig(WithMinSpecial(1))},
+		{"upper-case not enabled", NewConfig(WithModeMask(ModeLowerCase), WithMinUppercase(1))},
+		{"empty pool", NewConfig(WithModeMask(ModeNumeric), WithExcludeChars(CharRangeNumeric))},
+		{"minimums exceed length", NewConfig(WithFixedLength(2), WithMinNumeric(2), WithMinUppercase(1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := New(tt.config)
+			if _, err := generator.generatePronounceable(); !errors.Is(err, ErrUnsatisfiableConstraints) {
+				t.Errorf("generatePronounceable() expected ErrUnsatisfiableConstraints, got: %s", err)
+			}
+		})
+	}
+}
+
+func TestLengthStrategyFromString(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		want     LengthStrategy
+		wantErr  bool
+	}{
+		{"minimum", "minimum", LengthStrategyMinimum, false},
+		{"min", "Min", LengthStrategyMinimum, false},
+		{"exact", " exact ", LengthStrategyExact, false},
+		{"syllables", "syllables", LengthStrategySyllables, false},
+		{"syl", "syl", LengthStrategySyllables, false},
+		{"unknown", "maximum", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := LengthStrategyFromString(tt.strategy)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownLengthStrategy) {
+					t.Errorf("LengthStrategyFromString() expected ErrUnknownLengthStrategy, got: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LengthStrategyFromString() failed: %s", err)
+			}
+			if strategy != tt.want {
+				t.Errorf("LengthStrategyFromString() failed, expected: %s, got: %s", tt.want, strategy)
+			}
+		})
+	}
+}
+
+func TestExactLengthWeights(t *testing.T) {
+	// Combinations of length 3: "a-a-a" (weight 1), "a-bc" and "bc-a" (weight 2 each)
+	weights := exactLengthWeights(3, []string{"a", "bc"}, 0)
+	if weights[3].Int64() != 5 {
+		t.Errorf("exactLengthWeights() failed, expected: %d, got: %d", 5, weights[3].Int64())
+	}
+	// The expected amount of items is (1*3 + 2*2 + 2*2) / 5
+	if got := exactLengthItems(3, []string{"a", "bc"}, 0, weights); math.Abs(got-11.0/5.0) > 0.0001 {
+		t.Errorf("exactLengthItems() failed, expected: %f, got: %f", 11.0/5.0, got)
+	}
+	if weights = exactLengthWeights(1, []string{"bc"}, 0); weights[1].Sign() != 0 {
+		t.Errorf("exactLengthWeights() failed, expected: 0, got: %d", weights[1].Int64())
+	}
+	// With a separator of length 1, "a-bc" and "bc-a" are the only combinations of length
+	// 4 (plus one separator), with a weight of 2^3 each
+	if weights = exactLengthWeights(5, []string{"a", "bc"}, 1); weights[5].Int64() != 16 {
+		t.Errorf("exactLengthWeights() failed, expected: %d, got: %d", 16, weights[5].Int64())
+	}
+}
+
+func TestGeneratePronounceable_lengthStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy LengthStrategy
+		length   int64
+	}{
+		{"exact 1", LengthStrategyExact, 1},
+		{"exact 16", LengthStrategyExact, 16},
+		{"exact 31", LengthStrategyExact, 31},
+		{"syllables 5", LengthStrategySyllables, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig(WithFixedLength(tt.length), WithLengthStrategy(tt.strategy))
+			generator := New(config)
+			for range 50 {
+				password, err := generator.generatePronounceable()
+				if err != nil {
+					t.Fatalf("generatePronounceable() failed: %s", err)
+				}
+				got := int64(len(password))
+				if tt.strategy == LengthStrategySyllables {
+					got = int64(len(generator.syllables))
+				}
+				if got != tt.length {
+					t.Errorf("generatePronounceable() failed, expected length: %d, got: %d (%s)",
+						tt.length, got, password)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePronounceable_exactUnsatisfiable(t *testing.T) {
+	config := NewConfig(WithModeMask(ModeLowerCase), WithFixedLength(1), WithLengthStrategy(LengthStrategyExact))
+	generator := New(config)
+	if _, err := generator.generatePronounceable(); !errors.Is(err, ErrUnsatisfiableConstraints) {
+		t.Errorf("generatePronounceable() expected ErrUnsatisfiableConstraints, got: %s", err)
+	}
+	if _, err := generator.Entropy(); !errors.Is(err, ErrUnsatisfiableConstraints) {
+		t.Errorf("Entropy() expected ErrUnsatisfiableConstraints, got: %s", err)
+	}
+}
+
+func TestGenerator_Entropy_lengthStrategy(t *testing.T) {
+	for _, strategy := range []LengthStrategy{LengthStrategyMinimum, LengthStrategyExact, LengthStrategySyllables} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			generator := New(NewConfig(WithFixedLength(16), WithLengthStrategy(strategy)))
+			entropy, err := generator.Entropy()
+			if err != nil {
+				t.Fatalf("Entropy() failed: %s", err)
+			}
+			if entropy <= 0 {
+				t.Errorf("Entropy() expected positive entropy, got: %f", entropy)
+			}
+		})
+	}
+}
+
+func TestCapitalizationStyleFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   string
+		want    CapitalizationStyle
+		wantErr bool
+	}{
+		{"random-letter", "random-letter", CapitalizationRandomLetter, false},
+		{"letter", "Letter", CapitalizationRandomLetter, false},
+		{"none", "none", CapitalizationNone, false},
+		{"first-syllable", "first-syllable", CapitalizationFirstSyllable, false},
+		{"first", "first", CapitalizationFirstSyllable, false},
+		{"random-syllable", "random-syllable", CapitalizationRandomSyllable, false},
+		{"syllable", " syllable ", CapitalizationRandomSyllable, false},
+		{"unknown", "camel", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style, err := CapitalizationStyleFromString(tt.style)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownCapitalizationStyle) {
+					t.Errorf("CapitalizationStyleFromString() expected ErrUnknownCapitalizationStyle, got: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CapitalizationStyleFromString() failed: %s", err)
+			}
+			if style != tt.want {
+				t.Errorf("CapitalizationStyleFromString() failed, expected: %s, got: %s", tt.want, style)
+			}
+		})
+	}
+}
+
+func TestGeneratePronounceable_capitalization(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    CapitalizationStyle
+		maxUpper int
+	}{
+		{"none", CapitalizationNone, 0},
+		{"first syllable", CapitalizationFirstSyllable, 3},
+		{"random syllable", CapitalizationRandomSyllable, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig(WithModeMask(ModeLowerCase|ModeUpperCase), WithCapitalization(tt.style))
+			generator := New(config)
+			for range 50 {
+				password, err := generator.generatePronounceable()
+				if err != nil {
+					t.Fatalf("generatePronounceable() failed: %s", err)
+				}
+				upperSyllables := 0
+				for _, syllable := range generator.syllables {
+					if strings.ToLower(syllable) != syllable {
+						upperSyllables++
+					}
+				}
+				want := 1
+				if tt.style == CapitalizationNone {
+					want = 0
+				}
+				if upperSyllables != want {
+					t.Errorf("generatePronounceable() failed, expected %d upper-case syllables, got: %d (%s)",
+						want, upperSyllables, password)
+				}
+				if tt.style == CapitalizationFirstSyllable && strings.ToLower(generator.syllables[0]) ==
+					generator.syllables[0] {
+					t.Errorf("generatePronounceable() failed, expected first syllable to be upper-case, got: %s",
+						password)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePronounceable_capitalizationUnsatisfiable(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    CapitalizationStyle
+		minUpper int64
+	}{
+		{"none", CapitalizationNone, 1},
+		{"first syllable", CapitalizationFirstSyllable, 2},
+		{"random syllable", CapitalizationRandomSyllable, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig(WithCapitalization(tt.style), WithMinUppercase(tt.minUpper))
+			generator := New(config)
+			if _, err := generator.generatePronounceable(); !errors.Is(err, ErrUnsatisfiableConstraints) {
+				t.Errorf("generatePronounceable() expected ErrUnsatisfiableConstraints, got: %s", err)
+			}
+		})
+	}
+}
+
+func TestGeneratePronounceable_separator(t *testing.T) {
+	for _, strategy := range []LengthStrategy{LengthStrategyMinimum, LengthStrategyExact} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			config := NewConfig(WithFixedLength(16), WithLengthStrategy(strategy), WithSyllableSeparator("-"))
+			generator := New(config)
+			for range 50 {
+				password, err := generator.generatePronounceable()
+				if err != nil {
+					t.Fatalf("generatePronounceable() failed: %s", err)
+				}
+				if password != strings.Join(generator.syllables, "-") {
+					t.Errorf("generatePronounceable() failed, expected: %s, got: %s",
+						strings.Join(generator.syllables, "-"), password)
+				}
+				if strategy == LengthStrategyExact && len(password) != 16 {
+					t.Errorf("generatePronounceable() failed, expected length: %d, got: %d (%s)", 16,
+						len(password), password)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePronounceable_leetspeak(t *testing.T) {
+	config := NewConfig(WithModeMask(ModeLowerCase|ModeNumeric), WithLeetspeak(), WithFixedLength(100),
+		WithExcludeChars("3"))
+	generator := New(config)
+	password, err := generator.generatePronounceable()
+	if err != nil {
+		t.Fatalf("generatePronounceable() failed: %s", err)
+	}
+	if !strings.ContainsAny(password, "4578901") {
+		t.Errorf("generatePronounceable() failed, expected leetspeak substitutes, got: %s", password)
+	}
+	if strings.Contains(password, "3") {
+		t.Errorf("generatePronounceable() failed, expected no excluded substitutes, got: %s", password)
+	}
+
+	generator = New(NewConfig(WithModeMask(ModeLowerCase), WithLeetspeak()))
+	if positions := generator.leetspeakPositions("bat"); len(positions) != 0 {
+		t.Errorf("leetspeakPositions() failed, expected no positions without numbers, got: %v", positions)
+	}
+}
+
+func TestGenerator_Entropy_pronounceableStyles(t *testing.T) {
+	entropy := func(opts ...Option) float64 {
+		generator := New(NewConfig(append([]Option{WithFixedLength(5),
+			WithLengthStrategy(LengthStrategySyllables)}, opts...)...))
+		bits, err := generator.Entropy()
+		if err != nil {
+			t.Fatalf("Entropy() failed: %s", err)
+		}
+		return bits
+	}
+	base := entropy()
+	none := entropy(WithCapitalization(CapitalizationNone))
+	first := entropy(WithCapitalization(CapitalizationFirstSyllable))
+	random := entropy(WithCapitalization(CapitalizationRandomSyllable))
+	leet := entropy(WithLeetspeak())
+	separator := entropy(WithSyllableSeparator("-"))
+	if none != first {
+		t.Errorf("Entropy() failed, expected first syllable style to add no entropy, got: %f and %f", none, first)
+	}
+	if random <= none || base <= random {
+		t.Errorf("Entropy() failed, expected none (%f) < random syllable (%f) < random letter (%f)", none,
+			random, base)
+	}
+	if leet <= base {
+		t.Errorf("Entropy() failed, expected leetspeak (%f) to add entropy to %f", leet, base)
+	}
+	if separator != base {
+		t.Errorf("Entropy() failed, expected separator to add no entropy, got: %f and %f", separator, base)
+	}
+}
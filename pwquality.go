@@ -0,0 +1,433 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// DefaultPWQualityFile is the default location of the libpwquality configuration
+	// file, that is used by pam_pwquality on Linux systems
+	DefaultPWQualityFile = "/etc/security/pwquality.conf"
+	// pwqualityBaseMinLength is the minimum length libpwquality enforces, regardless
+	// of the configured minlen setting
+	pwqualityBaseMinLength = 6
+	// pwqualityDefaultMinLength is the default minlen value of libpwquality
+	pwqualityDefaultMinLength = 8
+	// cracklibMinDiff is the minimum amount of different characters cracklib requires
+	cracklibMinDiff = 5
+	// cracklibMaxStep is the maximum amount of sequential steps cracklib accepts
+	cracklibMaxStep = 4
+)
+
+// DefaultDictionaryFiles is a list of plain-text word lists, which are used for the
+// dictionary check of PWQuality if no DictionaryFile is set. The first existing file
+// is used.
+var DefaultDictionaryFiles = []string{
+	"/usr/share/dict/cracklib-small",
+	"/usr/share/dict/words",
+}
+
+// ErrPWQualityFailed is returned if a password does not pass the libpwquality checks
+var ErrPWQualityFailed = errors.New("password does not pass the pwquality checks")
+
+// PWQuality represents the password quality settings of a libpwquality configuration
+// as used by pam_pwquality.
+//
+// Credits follow the libpwquality semantics: a positive value is the maximum credit
+// a character class contributes to the length of the password, a negative value is
+// the minimum amount of characters of that class that are required.
+type PWQuality struct {
+	// DCredit represents the dcredit setting (digits)
+	DCredit int64
+	// DictCheck represents the dictcheck setting
+	DictCheck bool
+	// DictionaryFile is the path to a plain-text word list (one word per line) that
+	// is used for the dictionary check. If empty, DefaultDictionaryFiles are used
+	DictionaryFile string
+	// LCredit represents the lcredit setting (lower-case characters)
+	LCredit int64
+	// MaxClassRepeat represents the maxclassrepeat setting
+	MaxClassRepeat int64
+	// MaxRepeat represents the maxrepeat setting
+	MaxRepeat int64
+	// MaxSequence represents the maxsequence setting
+	MaxSequence int64
+	// MinClass represents the minclass setting
+	MinClass int64
+	// MinLen represents the minlen setting
+	MinLen int64
+	// OCredit represents the ocredit setting (other characters)
+	OCredit int64
+	// UCredit represents the ucredit setting (upper-case characters)
+	UCredit int64
+
+	// dictionary holds the words of the loaded dictionary file
+	dictionary map[string]struct{}
+	// dictOnce makes sure the dictionary file is only loaded once
+	dictOnce sync.Once
+}
+
+// NewPWQuality returns a new PWQuality instance with the libpwquality defaults
+func NewPWQuality() *PWQuality {
+	return &PWQuality{
+		DictCheck: true,
+		MinLen:    pwqualityDefaultMinLength,
+	}
+}
+
+// LoadPWQualityFile reads the libpwquality configuration from the file at the given
+// path. Like libpwquality, any "*.conf" file in the "<path>.d" directory is read
+// afterward in lexical order and overrides the previous settings.
+func LoadPWQualityFile(path string) (*PWQuality, error) {
+	pwq := NewPWQuality()
+	if err := pwq.readFile(path); err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(path + ".d/*.conf")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pwquality config directory: %w", err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if err = pwq.readFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return pwq, nil
+}
+
+// ParsePWQuality parses a libpwquality configuration from the given io.Reader.
+// Settings that are not supported by apg-go are ignored
+func ParsePWQuality(reader io.Reader) (*PWQuality, error) {
+	pwq := NewPWQuality()
+	if err := pwq.parse(reader); err != nil {
+		return nil, err
+	}
+	return pwq, nil
+}
+
+// WithPWQuality configures the generator to only generate passwords that pass the
+// given libpwquality settings. The settings are translated into the corresponding
+// Config values (length, minimum amounts, character modes and constraints) and
+// each generated password is additionally verified with PWQuality.Check.
+func WithPWQuality(pwq *PWQuality) Option {
+	return func(config *Config) {
+		if pwq == nil {
+			return
+		}
+		config.PWQuality = pwq
+
+		minLength := max(pwq.MinLen, pwqualityBaseMinLength)
+		config.MinLength = max(config.MinLength, minLength)
+		config.MaxLength = max(config.MaxLength, config.MinLength)
+		if config.FixedLength > 0 {
+			config.FixedLength = max(config.FixedLength, minLength)
+		}
+
+		for _, credit := range []struct {
+			value     int64
+			mode      Mode
+			minAmount *int64
+		}{
+			{pwq.DCredit, ModeNumeric, &config.MinNumeric},
+			{pwq.LCredit, ModeLowerCase, &config.MinLowerCase},
+			{pwq.OCredit, ModeSpecial, &config.MinSpecial},
+			{pwq.UCredit, ModeUpperCase, &config.MinUpperCase},
+		} {
+			if credit.value >= 0 {
+				continue
+			}
+			*credit.minAmount = max(*credit.minAmount, -credit.value)
+			config.Mode = MaskSetMode(config.Mode, credit.mode)
+		}
+
+		if pwq.MinClass > 0 {
+			config.MinClasses = max(config.MinClasses, min(pwq.MinClass, int64(len(charModes))))
+			config.Mode = maskEnsureModeCount(config.Mode, config.MinClasses)
+		}
+
+		config.MaxRepeat = minNonZero(config.MaxRepeat, pwq.MaxRepeat)
+		config.MaxClassRepeat = minNonZero(config.MaxClassRepeat, pwq.MaxClassRepeat)
+		config.MaxSequence = minNonZero(config.MaxSequence, pwq.MaxSequence)
+	}
+}
+
+// Check verifies the given password against the libpwquality settings. If the
+// password does not pass the checks, an error wrapping ErrPWQualityFailed and
+// describing the failed check is returned.
+func (p *PWQuality) Check(password string) error {
+	chars := []rune(password)
+	if isPalindrome(chars) {
+		return fmt.Errorf("%w: the password is a palindrome", ErrPWQualityFailed)
+	}
+
+	var digits, uppers, lowers, others int64
+	for _, char := range chars {
+		switch {
+		case unicode.IsDigit(char):
+			digits++
+		case unicode.IsUpper(char):
+			uppers++
+		case unicode.IsLower(char):
+			lowers++
+		default:
+			others++
+		}
+	}
+	size := max(p.MinLen, pwqualityBaseMinLength)
+	for _, class := range []struct {
+		name   string
+		count  int64
+		credit int64
+	}{
+		{"digits", digits, p.DCredit},
+		{"uppercase letters", uppers, p.UCredit},
+		{"lowercase letters", lowers, p.LCredit},
+		{"non-alphanumeric characters", others, p.OCredit},
+	} {
+		if class.credit >= 0 {
+			size -= min(class.count, class.credit)
+			continue
+		}
+		if class.count < -class.credit {
+			return fmt.Errorf("%w: the password contains less than %d %s", ErrPWQualityFailed,
+				-class.credit, class.name)
+		}
+	}
+	var classes int64
+	for _, count := range []int64{digits, uppers, lowers, others} {
+		if count > 0 {
+			classes++
+		}
+	}
+	if classes < p.MinClass {
+		return fmt.Errorf("%w: the password contains less than %d character classes",
+			ErrPWQualityFailed, p.MinClass)
+	}
+	if int64(len(chars)) < size {
+		return fmt.Errorf("%w: the password is shorter than %d characters", ErrPWQualityFailed, size)
+	}
+
+	if p.MaxRepeat > 0 && longestRepeat(chars) > p.MaxRepeat {
+		return fmt.Errorf("%w: the password contains more than %d same characters consecutively",
+			ErrPWQualityFailed, p.MaxRepeat)
+	}
+	if p.MaxClassRepeat > 0 && longestClassRepeat(chars, pwqualityClass) > p.MaxClassRepeat {
+		return fmt.Errorf("%w: the password contains more than %d characters of the same class "+
+			"consecutively", ErrPWQualityFailed, p.MaxClassRepeat)
+	}
+	if p.MaxSequence > 0 && longestMonotonicRun(chars) > p.MaxSequence {
+		return fmt.Errorf("%w: the password contains monotonic sequence longer than %d characters",
+			ErrPWQualityFailed, p.MaxSequence)
+	}
+
+	if p.DictCheck {
+		if err := p.checkDictionary(chars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDictionary performs the checks that cracklib performs on a password, when
+// the dictcheck setting is enabled
+func (p *PWQuality) checkDictionary(chars []rune) error {
+	if len(chars) < pwqualityBaseMinLength {
+		return fmt.Errorf("%w: it is too short", ErrPWQualityFailed)
+	}
+	if NewCharset(string(chars)).Len() < cracklibMinDiff {
+		return fmt.Errorf("%w: it does not contain enough DIFFERENT characters", ErrPWQualityFailed)
+	}
+	steps := 0
+	for i := 1; i < len(chars); i++ {
+		if chars[i] == chars[i-1]+1 || chars[i] == chars[i-1]-1 {
+			steps++
+		}
+	}
+	if steps > cracklibMaxStep {
+		return fmt.Errorf("%w: it is too simplistic/systematic", ErrPWQualityFailed)
+	}
+
+	p.dictOnce.Do(p.loadDictionary)
+	if len(p.dictionary) == 0 {
+		return nil
+	}
+	lower := strings.ToLower(string(chars))
+	stripped := strings.Map(func(char rune) rune {
+		if unicode.IsLetter(char) {
+			return char
+		}
+		return -1
+	}, lower)
+	for _, candidate := range []string{lower, reverseString(lower), stripped, reverseString(stripped)} {
+		if _, ok := p.dictionary[candidate]; ok {
+			return fmt.Errorf("%w: it is based on a dictionary word", ErrPWQualityFailed)
+		}
+	}
+	return nil
+}
+
+// loadDictionary loads the words of the dictionary file into the dictionary map.
+// If no dictionary file can be read, the dictionary stays empty.
+func (p *PWQuality) loadDictionary() {
+	files := DefaultDictionaryFiles
+	if p.DictionaryFile != "" {
+		files = []string{p.DictionaryFile}
+	}
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		p.dictionary = make(map[string]struct{})
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+			if len(word) >= pwqualityBaseMinLength-2 {
+				p.dictionary[word] = struct{}{}
+			}
+		}
+		_ = file.Close()
+		return
+	}
+}
+
+// readFile parses the libpwquality configuration file at the given path
+func (p *PWQuality) readFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open pwquality config file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return p.parse(file)
+}
+
+// parse parses the libpwquality configuration settings from the given io.Reader
+func (p *PWQuality) parse(reader io.Reader) error {
+	settings := map[string]func(int64){
+		"minlen":         func(value int64) { p.MinLen = value },
+		"dcredit":        func(value int64) { p.DCredit = value },
+		"ucredit":        func(value int64) { p.UCredit = value },
+		"lcredit":        func(value int64) { p.LCredit = value },
+		"ocredit":        func(value int64) { p.OCredit = value },
+		"minclass":       func(value int64) { p.MinClass = value },
+		"maxrepeat":      func(value int64) { p.MaxRepeat = value },
+		"maxclassrepeat": func(value int64) { p.MaxClassRepeat = value },
+		"maxsequence":    func(value int64) { p.MaxSequence = value },
+		"dictcheck":      func(value int64) { p.DictCheck = value != 0 },
+	}
+	scanner := bufio.NewScanner(reader)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if key == "" || !found {
+			continue
+		}
+
+		setting, ok := settings[key]
+		if !ok {
+			continue
+		}
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value for pwquality setting %q in line %d: %w", key, lineNum, err)
+		}
+		setting(number)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read pwquality config: %w", err)
+	}
+	return nil
+}
+
+// pwqualityClass returns the libpwquality character class of the given character
+func pwqualityClass(char rune) Mode {
+	switch {
+	case unicode.IsDigit(char):
+		return ModeNumeric
+	case unicode.IsUpper(char):
+		return ModeUpperCase
+	case unicode.IsLower(char):
+		return ModeLowerCase
+	default:
+		return ModeSpecial
+	}
+}
+
+// longestMonotonicRun returns the length of the longest run of characters in the
+// given slice, in which each character code is one above or one below the code of
+// the previous character
+func longestMonotonicRun(chars []rune) int64 {
+	if len(chars) == 0 {
+		return 0
+	}
+	var longest, up, down int64 = 1, 1, 1
+	for i := 1; i < len(chars); i++ {
+		switch chars[i] {
+		case chars[i-1] + 1:
+			up, down = up+1, 1
+		case chars[i-1] - 1:
+			up, down = 1, down+1
+		default:
+			up, down = 1, 1
+		}
+		longest = max(longest, up, down)
+	}
+	return longest
+}
+
+// isPalindrome returns true if the given slice of characters reads the same
+// backward as forward
+func isPalindrome(chars []rune) bool {
+	for i, j := 0, len(chars)-1; i < j; i, j = i+1, j-1 {
+		if chars[i] != chars[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// reverseString returns the given string in reverse order
+func reverseString(input string) string {
+	chars := []rune(input)
+	for i, j := 0, len(chars)-1; i < j; i, j = i+1, j-1 {
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+	return string(chars)
+}
+
+// minNonZero returns the smaller of the two given values, treating a zero value
+// as "not set"
+func minNonZero(a, b int64) int64 {
+	switch {
+	case a == 0:
+		return b
+	case b == 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPWQualityConf = `# Configuration for systemwide password quality limits
+minlen = 14
+dcredit = -2
+ucredit = -1
+# lcredit = -5
+lcredit = 1
+ocredit = -1
+minclass = 4
+maxrepeat = 2
+maxclassrepeat = 3
+maxsequence = 2
+dictcheck = 0
+difok = 5
+enforce_for_root
+`
+
+func TestParsePWQuality(t *testing.T) {
+	pwq, err := ParsePWQuality(strings.NewReader(testPWQualityConf))
+	if err != nil {
+		t.Fatalf("ParsePWQuality() failed: %s", err)
+	}
+	want := PWQuality{
+		DCredit: -2, LCredit: 1, MaxClassRepeat: 3, MaxRepeat: 2, MaxSequence: 2,
+		MinClass: 4, MinLen: 14, OCredit: -1, UCredit: -1,
+	}
+	if pwq.DCredit != want.DCredit || pwq.LCredit != want.LCredit || pwq.OCredit != want.OCredit ||
+		pwq.UCredit != want.UCredit || pwq.MinClass != want.MinClass || pwq.MinLen != want.MinLen ||
+		pwq.MaxRepeat != want.MaxRepeat || pwq.MaxClassRepeat != want.MaxClassRepeat ||
+		pwq.MaxSequence != want.MaxSequence || pwq.DictCheck {
+		t.Errorf("ParsePWQuality() failed, unexpected settings: %+v", pwq)
+	}
+}
+
+func TestParsePWQuality_defaults(t *testing.T) {
+	pwq, err := ParsePWQuality(strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("ParsePWQuality() failed: %s", err)
+	}
+	if pwq.MinLen != pwqualityDefaultMinLength {
+		t.Errorf("ParsePWQuality() failed, expected minlen: %d, got: %d", pwqualityDefaultMinLength,
+			pwq.MinLen)
+	}
+	if !pwq.DictCheck {
+		t.Error("ParsePWQuality() failed, expected dictcheck to be enabled by default")
+	}
+}
+
+func TestParsePWQuality_fails(t *testing.T) {
+	if _, err := ParsePWQuality(strings.NewReader("minlen = abc")); err == nil {
+		t.Error("ParsePWQuality() with invalid value was supposed to fail")
+	}
+}
+
+func TestLoadPWQualityFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pwquality.conf")
+	if err := os.WriteFile(path, []byte(testPWQualityConf), 0o600); err != nil {
+		t.Fatalf("failed to write test config: %s", err)
+	}
+	if err := os.Mkdir(path+".d", 0o700); err != nil {
+		t.Fatalf("failed to create test config directory: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(path+".d", "10-local.conf"), []byte("minlen = 16\n"),
+		0o600); err != nil {
+		t.Fatalf("failed to write test config: %s", err)
+	}
+	pwq, err := LoadPWQualityFile(path)
+	if err != nil {
+		t.Fatalf("LoadPWQualityFile() failed: %s", err)
+	}
+	if pwq.MinLen != 16 {
+		t.Errorf("LoadPWQualityFile() failed, expected minlen of config directory: %d, got: %d", 16,
+			pwq.MinLen)
+	}
+	if pwq.DCredit != -2 {
+		t.Errorf("LoadPWQualityFile() failed, expected dcredit: %d, got: %d", -2, pwq.DCredit)
+	}
+	if _, err = LoadPWQualityFile(filepath.Join(dir, "nonexisting.conf")); err == nil {
+		t.Error("LoadPWQualityFile() with non-existing file was supposed to fail")
+	}
+}
+
+func TestWithPWQuality(t *testing.T) {
+	pwq, err := ParsePWQuality(strings.NewReader(testPWQualityConf))
+	if err != nil {
+		t.Fatalf("ParsePWQuality() failed: %s", err)
+	}
+	c := NewConfig(WithMinLength(8), WithMaxLength(10), WithMaxRepeat(1), WithPWQuality(pwq))
+	if c.PWQuality != pwq {
+		t.Error("WithPWQuality() failed, expected PWQuality to be set")
+	}
+	if c.MinLength != 14 || c.MaxLength != 14 {
+		t.Errorf("WithPWQuality() failed, expected length: 14-14, got: %d-%d", c.MinLength, c.MaxLength)
+	}
+	if c.MinNumeric != 2 || c.MinUpperCase != 1 || c.MinSpecial != 1 || c.MinLowerCase != 0 {
+		t.Errorf("WithPWQuality() failed, unexpected minimum amounts: %d/%d/%d/%d", c.MinNumeric,
+			c.MinUpperCase, c.MinSpecial, c.MinLowerCase)
+	}
+	if !MaskHasMode(c.Mode, ModeSpecial) || c.MinClasses != 4 {
+		t.Errorf("WithPWQuality() failed, expected all character modes, got: %d", c.Mode)
+	}
+	if c.MaxRepeat != 1 || c.MaxClassRepeat != 3 || c.MaxSequence != 2 {
+		t.Errorf("WithPWQuality() failed, unexpected constraints: %d/%d/%d", c.MaxRepeat,
+			c.MaxClassRepeat, c.MaxSequence)
+	}
+	c = NewConfig(WithPWQuality(nil))
+	if c.PWQuality != nil {
+		t.Error("WithPWQuality(nil) failed, expected PWQuality to be nil")
+	}
+}
+
+func TestPWQuality_Check(t *testing.T) {
+	pwq, err := ParsePWQuality(strings.NewReader(testPWQualityConf))
+	if err != nil {
+		t.Fatalf("ParsePWQuality() failed: %s", err)
+	}
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"Valid password", "Xk9#mP2vLq8wRt", false},
+		{"Palindrome", "Ab1#5x9x5#1bA", true},
+		{"Too short", "Xk9#mP2vLq8", true},
+		{"Too short with credits", "Xk9#mP2vLq8wR", false},
+		{"Missing digits", "Xk9#mPavLqbwRtz", true},
+		{"Missing upper-case", "xk9#mp2vlq8wrt", true},
+		{"Missing other", "Xk9QmP2vLq8wRt", true},
+		{"Max repeat", "Xk9#mPPPvLq8wRt2", true},
+		{"Max class repeat", "Xk9#mP2vLqrwzRt8", true},
+		{"Max sequence", "Xk9#mP2vLq8wRtabc", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pwq.Check(tt.password)
+			if tt.wantErr && !errors.Is(err, ErrPWQualityFailed) {
+				t.Errorf("Check(%q) expected ErrPWQualityFailed, got: %s", tt.password, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Check(%q) failed: %s", tt.password, err)
+			}
+		})
+	}
+}
+
+func TestPWQuality_Check_dictionary(t *testing.T) {
+	dictFile := filepath.Join(t.TempDir(), "words")
+	if err := os.WriteFile(dictFile, []byte("password\nsecret\nZebras\n"), 0o600); err != nil {
+		t.Fatalf("failed to write test dictionary: %s", err)
+	}
+	pwq := NewPWQuality()
+	pwq.DictionaryFile = dictFile
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"Valid password", "Xk9#mP2vLq8w", false},
+		{"Dictionary word", "PassWord", true},
+		{"Reversed dictionary word", "sarbez", true},
+		{"Dictionary word with digits", "z3ebr4as", true},
+		{"Not enough different characters", "aaaabbbbcccc", true},
+		{"Too simplistic", "abcdefxz", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pwq.Check(tt.password)
+			if tt.wantErr && !errors.Is(err, ErrPWQualityFailed) {
+				t.Errorf("Check(%q) expected ErrPWQualityFailed, got: %s", tt.password, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Check(%q) failed: %s", tt.password, err)
+			}
+		})
+	}
+}
+
+func TestGenerateRandom_withPWQuality(t *testing.T) {
+	pwq, err := ParsePWQuality(strings.NewReader(testPWQualityConf))
+	if err != nil {
+		t.Fatalf("ParsePWQuality() failed: %s", err)
+	}
+	g := New(NewConfig(WithAlgorithm(AlgoRandom), WithPWQuality(pwq)))
+	for range 100 {
+		pw, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if err = pwq.Check(pw); err != nil {
+			t.Errorf("Generate() returned password that does not pass pwquality: %s", err)
+		}
+	}
+}
+
+func TestGeneratePronounceable_withPWQuality(t *testing.T) {
+	pwq, err := ParsePWQuality(strings.NewReader(testPWQualityConf))
+	if err != nil {
+		t.Fatalf("ParsePWQuality() failed: %s", err)
+	}
+	g := New(NewConfig(WithAlgorithm(AlgoPronounceable), WithPWQuality(pwq)))
+	for range 20 {
+		pw, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if err = pwq.Check(pw); err != nil {
+			t.Errorf("Generate() returned password that does not pass pwquality: %s", err)
+		}
+	}
+}
+
+func TestLongestMonotonicRun(t *testing.T) {
+	tests := []struct {
+		chars string
+		want  int64
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abc", 3},
+		{"9:;", 3},
+		{"cba1", 3},
+		{"abab", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.chars, func(t *testing.T) {
+			if got := longestMonotonicRun([]rune(tt.chars)); got != tt.want {
+				t.Errorf("longestMonotonicRun(%q) failed, expected: %d, got: %d", tt.chars, tt.want, got)
+			}
+		})
+	}
+}
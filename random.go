@@ -10,23 +10,23 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"os"
-	"regexp"
+	"math/bits"
 	"strings"
-)
 
-const (
-	// 7 bits to represent a letter index
-	letterIdxBits = 7
-	// All 1-bits, as many as letterIdxBits
-	letterIdxMask = 1<<letterIdxBits - 1
-	// # of letter indices fitting in 63 bits)
-	letterIdxMax = 63 / letterIdxBits
+	"golang.org/x/text/unicode/norm"
 )
 
 // maxInt32 is the maximum positive value for a int32 number type
 const maxInt32 = 2147483647
 
+// maxFilterAttempts is the maximum amount of passwords that are generated in an
+// attempt to find a password that passes all configured filters
+const maxFilterAttempts = 1000
+
+// maxPronounceableAttempts is the maximum amount of pronounceable passwords that are
+// generated in an attempt to find a password that meets the configured constraints
+const maxPronounceableAttempts = 10000
+
 var (
 	// ErrInvalidLength is returned if the provided maximum number is equal or less than zero
 	ErrInvalidLength = errors.New("provided length value cannot be zero or less")
@@ -34,6 +34,12 @@ var (
 	ErrLengthMismatch = errors.New("number of generated random bytes does not match the expected length")
 	// ErrInvalidCharRange is returned if the given range of characters is not valid
 	ErrInvalidCharRange = errors.New("provided character range is not valid or empty")
+	// ErrUnsatisfiableConstraints is returned if the configured password constraints
+	// can never be met by the generator
+	ErrUnsatisfiableConstraints = errors.New("password constraints cannot be satisfied with the given configuration")
+	// ErrFilterAttemptsExceeded is returned if no password that passes all configured
+	// filters could be generated
+	ErrFilterAttemptsExceeded = errors.New("failed to generate a password that passes all filters")
 )
 
 // CoinFlip performs a simple coinflip based on the rand library and returns 1 or 0
@@ -48,8 +54,42 @@ func (g *Generator) CoinFlipBool() bool {
 }
 
 // Generate generates a password based on all the different config flags and returns
-// it as string type. If the generation fails, an error will be thrown
+// it as string type. If filters are configured, passwords are generated until one
+// passes all filters. If the generation fails, an error will be thrown
 func (g *Generator) Generate() (string, error) {
+	if len(g.config.Filters) == 0 {
+		return g.generate()
+	}
+	var err error
+	for range maxFilterAttempts {
+		var password string
+		password, err = g.generate()
+		if err != nil {
+			return "", err
+		}
+		if err = g.applyFilters(password); err == nil {
+			return password, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %w", ErrFilterAttemptsExceeded, err)
+}
+
+// applyFilters applies all configured filters to the given password and returns
+// the error of the first filter that the password does not pass
+func (g *Generator) applyFilters(password string) error {
+	for _, filter := range g.config.Filters {
+		if filter == nil {
+			continue
+		}
+		if err := filter(password); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generate generates a password based on the configured algorithm
+func (g *Generator) generate() (string, error) {
 	switch g.config.Algorithm {
 	case AlgoPronounceable:
 		return g.generatePronounceable()
@@ -66,53 +106,50 @@ func (g *Generator) Generate() (string, error) {
 	}
 }
 
-// GetCharRangeFromConfig checks the Mode from the Config and returns a
-// list of all possible characters that are supported by these Mode
+// GetCharRangeFromConfig checks the Mode and the custom character classes from
+// the Config and returns a list of all possible characters that are supported by
+// these Mode and classes, without the excluded and confusable characters. If
+// keyboard layouts are configured, only characters that are safe to type in all
+// of the layouts are part of the list. Every character (grapheme cluster) is only
+// part of the list once, so that emojis with skin tone modifiers or zero-width
+// joiner sequences are kept intact
 func (g *Generator) GetCharRangeFromConfig() string {
-	charRange := strings.Builder{}
-	if MaskHasMode(g.config.Mode, ModeLowerCase) {
-		switch MaskHasMode(g.config.Mode, ModeHumanReadable) {
-		case true:
-			charRange.WriteString(CharRangeAlphaLowerHuman)
-		default:
-			charRange.WriteString(CharRangeAlphaLower)
-		}
-	}
-	if MaskHasMode(g.config.Mode, ModeNumeric) {
-		switch MaskHasMode(g.config.Mode, ModeHumanReadable) {
-		case true:
-			charRange.WriteString(CharRangeNumericHuman)
-		default:
-			charRange.WriteString(CharRangeNumeric)
+	var charRanges []string
+	for _, mode := range charModes {
+		if MaskHasMode(g.config.Mode, mode) {
+			charRanges = append(charRanges, g.charRangeForMode(mode))
 		}
 	}
-	if MaskHasMode(g.config.Mode, ModeSpecial) {
-		switch MaskHasMode(g.config.Mode, ModeHumanReadable) {
-		case true:
-			charRange.WriteString(CharRangeSpecialHuman)
-		default:
-			charRange.WriteString(CharRangeSpecial)
-		}
+	for _, class := range g.config.CharClasses {
+		charRanges = append(charRanges, class.Chars)
 	}
-	if MaskHasMode(g.config.Mode, ModeUpperCase) {
-		switch MaskHasMode(g.config.Mode, ModeHumanReadable) {
-		case true:
-			charRange.WriteString(CharRangeAlphaUpperHuman)
-		default:
-			charRange.WriteString(CharRangeAlphaUpper)
-		}
+
+	isExcluded := g.isExcludedFunc()
+	isUnsafe := func(char rune) bool { return isExcluded(char) }
+	if len(g.config.KeyboardLayouts) > 0 {
+		// Unknown keyboard layouts result in an empty list and are reported by
+		// validateConstraints
+		keyboardSafe, _ := KeyboardSafeCharset(g.config.KeyboardLayouts...)
+		isUnsafe = func(char rune) bool { return isExcluded(char) || !keyboardSafe.Contains(char) }
 	}
-	if g.config.ExcludeChars != "" {
-		rex, err := regexp.Compile("[" + regexp.QuoteMeta(g.config.ExcludeChars) + "]")
-		if err == nil {
-			newRange := rex.ReplaceAllLiteralString(charRange.String(), "")
-			charRange.Reset()
-			charRange.WriteString(newRange)
-		} else {
-			_, _ = fmt.Fprintf(os.Stderr, "failed to exclude characters: %s\n", err)
+	var builder strings.Builder
+	seen := make(map[string]struct{})
+	for _, charRange := range charRanges {
+		for _, cluster := range graphemeClusters(norm.NFC.String(charRange)) {
+			if _, ok := seen[cluster]; ok || strings.IndexFunc(cluster, isUnsafe) >= 0 {
+				continue
+			}
+			seen[cluster] = struct{}{}
+			builder.WriteString(cluster)
 		}
 	}
-	return charRange.String()
+	return builder.String()
+}
+
+// CharsetFromConfig returns the Charset of all characters (Unicode code points)
+// of the character range returned by GetCharRangeFromConfig
+func (g *Generator) CharsetFromConfig() Charset {
+	return NewCharset(g.GetCharRangeFromConfig())
 }
 
 // GetPasswordLength returns the password length based on the given config
@@ -169,14 +206,39 @@ func (g *Generator) RandNum(max int64) (int64, error) {
 	return randNum.Int64(), nil
 }
 
-// RandomStringFromCharRange returns a random string of length l based of the range of characters given.
-// The method makes use of the crypto/random package and therfore is
+// RandomStringFromCharset returns a random string of the given length based on the characters of
+// the given Charset. The method makes use of the crypto/random package and therefore is
 // cryptographically secure
+func (g *Generator) RandomStringFromCharset(length int64, charset Charset) (string, error) {
+	return g.RandomStringFromCharRange(length, charset.String())
+}
+
+// RandomStringFromCharRange returns a random string of length l based of the range of characters given.
+// The range of characters is normalized to NFC and split into user-perceived characters (grapheme
+// clusters), so that multi-byte and multi-code-point characters (i. e. emojis) are never split
+// apart. The length is the amount of grapheme clusters. Each character is selected with equal
+// probability, regardless of the size of the range.
+//
+// Please note that repetitions and unique characters are checked per grapheme cluster, while
+// the other password constraints and minimum character amounts of the generator's configuration
+// are checked per code point. Every code point of a multi-code-point character counts
+// individually towards sequences, character modes and amounts.
+//
+// The method makes use of the crypto/random package and therefore is cryptographically secure
 func (g *Generator) RandomStringFromCharRange(length int64, charRange string) (string, error) {
 	if length < 1 {
 		return "", ErrInvalidLength
 	}
-	if len(charRange) < 1 {
+	return g.randomStringFromChars(length, graphemeClusters(norm.NFC.String(charRange)))
+}
+
+// randomStringFromChars returns a random string of the given length based on the given
+// list of characters. Each character is selected with equal probability
+func (g *Generator) randomStringFromChars(length int64, chars []string) (string, error) {
+	if length < 1 {
+		return "", ErrInvalidLength
+	}
+	if len(chars) < 1 {
 		return "", ErrInvalidCharRange
 	}
 	randString := strings.Builder{}
@@ -187,26 +249,32 @@ func (g *Generator) RandomStringFromCharRange(length int64, charRange string) (s
 		randString.Grow(int(length))
 	}
 
-	charRangeLength := len(charRange)
+	// We use as many random bits per character as needed to represent every index
+	// of the character range and discard indices that are out of range. This way
+	// the index selection is unbiased for any size of the character range
+	charsLength := uint64(len(chars))
+	idxBits := max(bits.Len64(charsLength-1), 1)
+	idxMask := uint64(1)<<idxBits - 1
+	idxMax := 64 / idxBits
 
 	randPool := make([]byte, 8)
 	_, err := rand.Read(randPool)
 	if err != nil {
 		return randString.String(), err
 	}
-	for idx, char, rest := length-1, binary.BigEndian.Uint64(randPool), letterIdxMax; idx >= 0; {
+	for idx, char, rest := length-1, binary.BigEndian.Uint64(randPool), idxMax; idx >= 0; {
 		if rest == 0 {
 			_, err = rand.Read(randPool)
 			if err != nil {
 				return randString.String(), err
 			}
-			char, rest = binary.BigEndian.Uint64(randPool), letterIdxMax
+			char, rest = binary.BigEndian.Uint64(randPool), idxMax
 		}
-		if i := int(char & letterIdxMask); i < charRangeLength {
-			randString.WriteByte(charRange[i])
+		if i := char & idxMask; i < charsLength {
+			randString.WriteString(chars[i])
 			idx--
 		}
-		char >>= letterIdxBits
+		char >>= idxBits
 		rest--
 	}
 
@@ -217,59 +285,44 @@ func (g *Generator) RandomStringFromCharRange(length int64, charRange string) (s
 // generator's configuration. It returns true if the password meets the requirements, otherwise it
 // returns false.
 //
-// The minimum requirements for each character type (lowercase, numeric, special, uppercase) are
-// checked independently. For each character type, the corresponding character range is determined
-// based on the generator's configuration. The password is then checked for the presence of each
-// character in the character range, and a count is maintained.
+// The minimum requirements for each character type (lowercase, numeric, special, uppercase) and
+// each custom character class are checked independently. For each character type, the
+// corresponding character range is determined based on the generator's configuration. The
+// password is then checked for the presence of each character in the character range, and a
+// count is maintained.
 func (g *Generator) checkMinimumRequirements(password string) bool {
 	ok := true
 	if g.config.MinLowerCase > 0 {
-		var charRange string
-		switch MaskHasMode(g.config.Mode, ModeHumanReadable) {
-		case true:
-			charRange = CharRangeAlphaLowerHuman
-		default:
-			charRange = CharRangeAlphaLower
-		}
-
-		matchesMinimumAmount(charRange, password, g.config.MinLowerCase, &ok)
+		matchesMinimumAmount(g.charRangeForMode(ModeLowerCase), password, g.config.MinLowerCase, &ok)
 	}
 	if g.config.MinNumeric > 0 {
-		var charRange string
-		switch MaskHasMode(g.config.Mode, ModeHumanReadable) {
-		case true:
-			charRange = CharRangeNumericHuman
-		default:
-			charRange = CharRangeNumeric
-		}
-
-		matchesMinimumAmount(charRange, password, g.config.MinNumeric, &ok)
+		matchesMinimumAmount(g.charRangeForMode(ModeNumeric), password, g.config.MinNumeric, &ok)
 	}
 	if g.config.MinSpecial > 0 {
-		var charRange string
-		switch MaskHasMode(g.config.Mode, ModeHumanReadable) {
-		case true:
-			charRange = CharRangeSpecialHuman
-		default:
-			charRange = CharRangeSpecial
-		}
-
-		matchesMinimumAmount(charRange, password, g.config.MinSpecial, &ok)
+		matchesMinimumAmount(g.charRangeForMode(ModeSpecial), password, g.config.MinSpecial, &ok)
 	}
 	if g.config.MinUpperCase > 0 {
-		var charRange string
-		switch MaskHasMode(g.config.Mode, ModeHumanReadable) {
-		case true:
-			charRange = CharRangeAlphaUpperHuman
-		default:
-			charRange = CharRangeAlphaUpper
+		matchesMinimumAmount(g.charRangeForMode(ModeUpperCase), password, g.config.MinUpperCase, &ok)
+	}
+	for _, class := range g.config.CharClasses {
+		if class.MinAmount > 0 {
+			matchesMinimumAmount(NewCharset(class.Chars).String(), password, class.MinAmount, &ok)
 		}
-
-		matchesMinimumAmount(charRange, password, g.config.MinUpperCase, &ok)
 	}
 	return ok
 }
 
+// charRangeForMode returns the range of characters that represents the given
+// character Mode. If a custom character range is configured for the Mode, it is
+// used instead of the built-in range. Otherwise the built-in range is selected
+// based on the human-readable and dictation settings of the generator's configuration
+func (g *Generator) charRangeForMode(mode Mode) string {
+	if charRange, ok := g.config.CharRanges[mode]; ok {
+		return NewCharset(charRange).String()
+	}
+	return builtinCharRangeForMode(mode, g.config.Mode)
+}
+
 // generateCoinFlip is executed when Generate() is called with Algorithm set
 // to AlgoCoinFlip
 func (g *Generator) generateCoinFlip() (string, error) {
@@ -290,32 +343,37 @@ func (g *Generator) generatePronounceable() (string, error) {
 		return "", fmt.Errorf("failed to calculate password length: %w", err)
 	}
 
-	characterSet := KoremutakeSyllables
-	characterSet = append(characterSet, strings.Split(CharRangeNumericHuman, "")...)
-	characterSet = append(characterSet, strings.Split(CharRangeSpecialHuman, "")...)
-	characterSetLength := len(characterSet)
-	for int64(len(password)) < length {
-		randNum, err := g.RandNum(int64(characterSetLength))
+	characterSet := g.pronounceablePool()
+	if err = g.validatePronounceable(length, characterSet); err != nil {
+		return "", err
+	}
+	var weights []*big.Int
+	if g.config.LengthStrategy == LengthStrategyExact {
+		weights = exactLengthWeights(length+g.separatorLength(), characterSet, g.separatorLength())
+		if weights[length+g.separatorLength()].Sign() == 0 {
+			return "", ErrUnsatisfiableConstraints
+		}
+	}
+	// The syllables do not allow to validate the constraints upfront, therefore the
+	// amount of attempts is limited
+	for range maxPronounceableAttempts {
+		items, err := g.pronounceableItems(length, characterSet, weights)
 		if err != nil {
 			return "", fmt.Errorf("failed to generate a random number for Koremutake syllable generation: %w",
 				err)
 		}
-		nextSyllable := characterSet[randNum]
-		if g.CoinFlipBool() {
-			syllableLength := len(nextSyllable)
-			characterPosition, err := g.RandNum(int64(syllableLength))
-			if err != nil {
-				return "", fmt.Errorf("failed to generate a random number for Koremutake syllable generation: %w",
-					err)
-			}
-			randomChar := string(nextSyllable[characterPosition])
-			nextSyllable = strings.ReplaceAll(nextSyllable, randomChar, strings.ToUpper(randomChar))
+		g.syllables, err = g.styleSyllables(items)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate a random number for Koremutake syllable generation: %w",
+				err)
+		}
+		password = strings.Join(g.syllables, g.config.SyllableSeparator)
+		if g.checkMinimumRequirements(password) && g.checkConstraints(password) {
+			return password, nil
 		}
-		password += nextSyllable
-		g.syllables = append(g.syllables, nextSyllable)
 	}
-
-	return password, nil
+	return "", fmt.Errorf("%w: no pronounceable password met the constraints after %d attempts",
+		ErrUnsatisfiableConstraints, maxPronounceableAttempts)
 }
 
 // generateBinary is executed when Generate() is called with Algorithm set
@@ -340,24 +398,75 @@ func (g *Generator) generateBinary() (string, error) {
 // to AlgoRandom
 func (g *Generator) generateRandom() (string, error) {
 	length, err := g.GetPasswordLength()
+	if g.config.CheckCharGroupSize > 0 {
+		length, err = g.checkCharPayloadLength()
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to calculate password length: %w", err)
 	}
 	charRange := g.GetCharRangeFromConfig()
+	chars := graphemeClusters(norm.NFC.String(charRange))
+	classes, err := g.weightedClasses(chars)
+	if err != nil {
+		return "", err
+	}
+	// Characters of classes with a weight of zero are never selected and therefore
+	// are not available to satisfy the constraints
+	availableRange := charRange
+	if classes != nil {
+		available := strings.Builder{}
+		for _, class := range classes {
+			available.WriteString(strings.Join(class.chars, ""))
+		}
+		availableRange = available.String()
+	}
+	if err = g.validateConstraints(length, availableRange); err != nil {
+		return "", err
+	}
 	var password string
 	var ok bool
 	for !ok {
-		password, err = g.RandomStringFromCharRange(length, charRange)
+		if classes != nil {
+			password, err = g.randomStringFromClasses(length, classes)
+		} else {
+			password, err = g.randomStringFromChars(length, chars)
+		}
 		if err != nil {
 			return "", err
 		}
-		ok = g.checkMinimumRequirements(password)
+		ok = g.checkMinimumRequirements(password) && g.checkConstraints(password)
+		if !ok {
+			continue
+		}
+		if g.config.MobileGrouping {
+			password = GroupCharsForMobile(password)
+		}
+		// The check characters are part of the password and have to meet the
+		// constraints as well
+		if g.config.CheckCharGroupSize > 0 {
+			if password, err = AddCheckChars(password, charRange, g.config.CheckCharGroupSize); err != nil {
+				return "", err
+			}
+			ok = g.checkCheckCharConstraints(password)
+		}
 	}
+	return password, nil
+}
 
-	if g.config.MobileGrouping {
-		return GroupCharsForMobile(password), nil
+// checkCharPayloadLength returns a random length for the random part of a password
+// with check characters, so that the length of the password including the check
+// characters and separators meets the configured length
+func (g *Generator) checkCharPayloadLength() (int64, error) {
+	lengths := g.passwordLengths()
+	if len(lengths) == 0 {
+		return 0, fmt.Errorf("%w: no password with check characters in groups of %d has the "+
+			"configured length", ErrUnsatisfiableConstraints, g.config.CheckCharGroupSize)
 	}
-	return password, nil
+	index, err := g.RandNum(int64(len(lengths)))
+	if err != nil {
+		return 0, err
+	}
+	return lengths[index], nil
 }
 
 // matchesMinimumAmount checks if the number of occurrences of characters in
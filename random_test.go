@@ -154,6 +154,118 @@ func TestGenerator_RandomString(t *testing.T) {
 	}
 }
 
+func TestGenerator_RandomString_unicode(t *testing.T) {
+	generator := New(NewConfig())
+	tests := []struct {
+		name      string
+		charRange string
+		chars     []string
+	}{
+		{"Unicode letters", "äöüßλж", []string{"ä", "ö", "ü", "ß", "λ", "ж"}},
+		{"Emoji", "👍🏽👨‍👩‍👧🇩🇪", []string{"👍🏽", "👨‍👩‍👧", "🇩🇪"}},
+		{"NFC normalization", "e\u0301a\u0308", []string{"\u00e9", "\u00e4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password, err := generator.RandomStringFromCharRange(50, tt.charRange)
+			if err != nil {
+				t.Fatalf("RandomStringFromCharRange() failed: %s", err)
+			}
+			clusters := graphemeClusters(password)
+			if len(clusters) != 50 {
+				t.Errorf("RandomStringFromCharRange() failed, expected: %d characters, got: %d", 50,
+					len(clusters))
+			}
+			for _, cluster := range clusters {
+				found := false
+				for _, char := range tt.chars {
+					if cluster == char {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("RandomStringFromCharRange() failed, unexpected character: %q", cluster)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_emoji(t *testing.T) {
+	tests := []struct {
+		name  string
+		chars []string
+	}{
+		{"Skin tone modifiers", []string{"👍🏽", "👍🏿", "❤️"}},
+		{"Zero-width joiner sequences", []string{"👨‍👩‍👧", "👩‍👩‍👦", "👍"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(0),
+				WithCharClass("emoji", strings.Join(tt.chars, ""), 0), WithFixedLength(20))
+			generator := New(config)
+			if charRange := generator.GetCharRangeFromConfig(); charRange != strings.Join(tt.chars, "") {
+				t.Errorf("GetCharRangeFromConfig() failed, expected range %q, got %q",
+					strings.Join(tt.chars, ""), charRange)
+			}
+			password, err := generator.Generate()
+			if err != nil {
+				t.Fatalf("Generate() failed: %s", err)
+			}
+			clusters := graphemeClusters(password)
+			if len(clusters) != 20 {
+				t.Errorf("Generate() failed, expected: %d characters, got: %d", 20, len(clusters))
+			}
+			for _, cluster := range clusters {
+				found := false
+				for _, char := range tt.chars {
+					if cluster == char {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Generate() failed, unexpected character: %q", cluster)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_RandomString_largeCharset(t *testing.T) {
+	generator := New(NewConfig())
+	charset, err := CharsetFromCategory("Greek")
+	if err != nil {
+		t.Fatalf("CharsetFromCategory() failed: %s", err)
+	}
+	charset = charset.Union(NewCharset(CharRangeAlphaLower, CharRangeAlphaUpper, CharRangeNumeric,
+		CharRangeSpecial))
+	if charset.Len() <= 256 {
+		t.Fatalf("expected a charset with more than 256 characters, got: %d", charset.Len())
+	}
+	password, err := generator.RandomStringFromCharset(2000, charset)
+	if err != nil {
+		t.Fatalf("RandomStringFromCharset() failed: %s", err)
+	}
+	ascii := NewCharset(CharRangeAlphaLower, CharRangeAlphaUpper, CharRangeNumeric, CharRangeSpecial)
+	nonASCII := 0
+	for _, char := range password {
+		if !charset.Contains(char) {
+			t.Fatalf("RandomStringFromCharset() failed, unexpected character: %q", char)
+		}
+		if !ascii.Contains(char) {
+			nonASCII++
+		}
+	}
+	// The Greek characters make up the largest part of the charset, so they are
+	// expected to make up the largest part of the password as well
+	if nonASCII < 1000 {
+		t.Errorf("RandomStringFromCharset() failed, expected most characters to be from the end of the "+
+			"charset, got: %d of %d", nonASCII, 2000)
+	}
+}
+
 func TestGetCharRangeFromConfig(t *testing.T) {
 	config := NewConfig()
 	generator := New(config)
@@ -220,6 +332,70 @@ func TestGetCharRangeFromConfig(t *testing.T) {
 	}
 }
 
+func TestGetCharRangeFromConfig_CustomCharRanges(t *testing.T) {
+	config := NewConfig(WithModeMask(ModeLowerCase|ModeSpecial|ModeHumanReadable),
+		WithCharRange(ModeLowerCase, "abcabc"), WithCharRange(ModeSpecial, "!?"),
+		WithCharClass("brackets", "()[]?", 1))
+	generator := New(config)
+	expected := "abc!?()[]"
+	if charRange := generator.GetCharRangeFromConfig(); charRange != expected {
+		t.Errorf("GetCharRangeFromConfig() failed, expected range %s, got %s", expected, charRange)
+	}
+}
+
+func TestGenerateRandom_CharClass(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithCharClass("brackets", "()[]{}", 3),
+		WithCharRange(ModeUpperCase, "XYZ"))
+	generator := New(config)
+	for range 100 {
+		password, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		count := 0
+		for _, char := range password {
+			if strings.ContainsRune("()[]{}", char) {
+				count++
+			}
+		}
+		if count < 3 {
+			t.Errorf("Generate() failed, expected at least 3 characters of the class, got: %d", count)
+		}
+		if strings.ContainsAny(password, "ABCDEFGHIJKLMNOPQRSTUVW") {
+			t.Errorf("Generate() failed, password %q contains characters outside the custom range",
+				password)
+		}
+	}
+}
+
+func TestGenerateRandom_CharClassInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		wantErr error
+	}{
+		{"Empty class", []Option{WithCharClass("empty", "", 0)}, ErrInvalidCharRange},
+		{"Non-printable class", []Option{WithCharClass("tab", "\t", 0)}, ErrInvalidCharRange},
+		{"Invalid custom range", []Option{WithCharRange(ModeLowerCase, "a b")}, ErrInvalidCharRange},
+		{
+			"Excluded class", []Option{WithCharClass("brackets", "()", 1), WithExcludeChars("()")},
+			ErrUnsatisfiableConstraints,
+		},
+		{
+			"Minimum too high", []Option{WithCharClass("brackets", "()", 30), WithMaxLength(20)},
+			ErrUnsatisfiableConstraints,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := New(NewConfig(append([]Option{WithAlgorithm(AlgoRandom)}, tt.options...)...))
+			if _, err := generator.Generate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Generate() failed, expected error: %s, got: %s", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestGetCharRangeFromConfig_ExcludeChar(t *testing.T) {
 	defaultConf := NewConfig()
 	defaultGen := New(defaultConf)
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultRotationMinDistance is the default minimum Levenshtein distance between
+	// the previous and the new password
+	DefaultRotationMinDistance int64 = 4
+	// DefaultRotationMaxSharedSubstring is the default maximum length of a substring
+	// that the previous and the new password are allowed to share
+	DefaultRotationMaxSharedSubstring int64 = 3
+)
+
+// ErrRotationFailed is returned if a new password does not differ sufficiently from
+// the previous password
+var ErrRotationFailed = errors.New("password does not differ sufficiently from the previous password")
+
+// Rotation represents the requirements a new password has to meet when it replaces
+// a previous password. The new password needs to have a minimum Levenshtein distance
+// to the previous password, must not share a substring longer than the given maximum
+// with it and must not be a simple case or increment variant (i. e. "Summer2023" and
+// "summer2024") of it.
+type Rotation struct {
+	// MaxSharedSubstring is the maximum length of a substring that the previous
+	// and the new password are allowed to share. The check is case-insensitive.
+	// A zero value disables the check
+	MaxSharedSubstring int64
+	// MinDistance is the minimum Levenshtein distance between the previous and the
+	// new password. A zero value disables the check
+	MinDistance int64
+	// Previous is the previous password
+	Previous string
+}
+
+// NewRotation returns a new Rotation for the given previous password with the
+// default rotation requirements
+func NewRotation(previous string) *Rotation {
+	return &Rotation{
+		MaxSharedSubstring: DefaultRotationMaxSharedSubstring,
+		MinDistance:        DefaultRotationMinDistance,
+		Previous:           previous,
+	}
+}
+
+// WithRotation configures the generator to only return passwords that differ
+// sufficiently from the previous password of the given Rotation. The check is
+// applied as a Filter on top of the generated passwords.
+func WithRotation(rotation *Rotation) Option {
+	return func(config *Config) {
+		if rotation == nil {
+			return
+		}
+		config.Filters = append(config.Filters, rotation.Check)
+	}
+}
+
+// Check verifies that the given password differs sufficiently from the previous
+// password. If it does not, an error wrapping ErrRotationFailed and describing
+// the failed check is returned.
+func (r *Rotation) Check(password string) error {
+	if r.Previous == "" {
+		return nil
+	}
+	if strings.EqualFold(password, r.Previous) {
+		return fmt.Errorf("%w: the password is a case variant of the previous password",
+			ErrRotationFailed)
+	}
+	if isIncrementVariant(password, r.Previous) {
+		return fmt.Errorf("%w: the password is an increment variant of the previous password",
+			ErrRotationFailed)
+	}
+	if r.MinDistance > 0 && levenshteinDistance(password, r.Previous) < r.MinDistance {
+		return fmt.Errorf("%w: the password differs in less than %d characters from the previous password",
+			ErrRotationFailed, r.MinDistance)
+	}
+	if r.MaxSharedSubstring > 0 && longestCommonSubstring(strings.ToLower(password),
+		strings.ToLower(r.Previous)) > r.MaxSharedSubstring {
+		return fmt.Errorf("%w: the password shares more than %d consecutive characters with the "+
+			"previous password", ErrRotationFailed, r.MaxSharedSubstring)
+	}
+	return nil
+}
+
+// isIncrementVariant returns true if both given passwords only differ in the numbers
+// they contain (and the case of the characters), i. e. "Summer2023" and "summer2024"
+func isIncrementVariant(a, b string) bool {
+	skeletonA, numbersA := numberSkeleton(a)
+	skeletonB, numbersB := numberSkeleton(b)
+	if numbersA == 0 || numbersA != numbersB {
+		return false
+	}
+	return skeletonA == skeletonB
+}
+
+// numberSkeleton returns the lower-case version of the given string in which each
+// run of digits is replaced by a single NUL character, as well as the amount of
+// digit runs that have been replaced
+func numberSkeleton(input string) (string, int) {
+	var skeleton strings.Builder
+	runs := 0
+	inNumber := false
+	for _, char := range input {
+		if unicode.IsDigit(char) {
+			if !inNumber {
+				skeleton.WriteRune(0)
+				runs++
+			}
+			inNumber = true
+			continue
+		}
+		inNumber = false
+		skeleton.WriteRune(unicode.ToLower(char))
+	}
+	return skeleton.String(), runs
+}
+
+// levenshteinDistance returns the Levenshtein distance between both given strings
+func levenshteinDistance(a, b string) int64 {
+	charsA, charsB := []rune(a), []rune(b)
+	previous := make([]int64, len(charsB)+1)
+	current := make([]int64, len(charsB)+1)
+	for j := range previous {
+		previous[j] = int64(j)
+	}
+	for i := range charsA {
+		current[0] = int64(i + 1)
+		for j := range charsB {
+			cost := int64(1)
+			if charsA[i] == charsB[j] {
+				cost = 0
+			}
+			current[j+1] = min(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(charsB)]
+}
+
+// longestCommonSubstring returns the length of the longest substring that is part
+// of both given strings
+func longestCommonSubstring(a, b string) int64 {
+	charsA, charsB := []rune(a), []rune(b)
+	var longest int64
+	previous := make([]int64, len(charsB)+1)
+	current := make([]int64, len(charsB)+1)
+	for i := range charsA {
+		for j := range charsB {
+			current[j+1] = 0
+			if charsA[i] == charsB[j] {
+				current[j+1] = previous[j] + 1
+				longest = max(longest, current[j+1])
+			}
+		}
+		previous, current = current, previous
+	}
+	return longest
+}
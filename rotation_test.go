@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRotation_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		password string
+		wantErr  bool
+	}{
+		{"Different password", "Xk9mP2vLqrwz", "h7TgBn4cRfeS", false},
+		{"Empty previous password", "", "h7TgBn4cRfeS", false},
+		{"Identical password", "Xk9mP2vLqrwz", "Xk9mP2vLqrwz", true},
+		{"Case variant", "Xk9mP2vLqrwz", "xK9Mp2VlQRWZ", true},
+		{"Increment variant", "Summer2023!", "summer2024!", true},
+		{"Increment variant with multiple numbers", "a1b2c3d4e5", "a2b3c4d5e6", true},
+		{"Small distance", "Xk9mP2vLqrwz", "Xk9mP2vLqr4z", true},
+		{"Shared substring", "Xk9mP2vLqrwz", "h7TgqrwzRfeS", true},
+		{"Shared substring case-insensitive", "Xk9mP2vLqrwz", "h7TgQRWZRfeS", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewRotation(tt.previous).Check(tt.password)
+			if tt.wantErr && !errors.Is(err, ErrRotationFailed) {
+				t.Errorf("Check() failed, expected error: %s, got: %s", ErrRotationFailed, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Check() failed: %s", err)
+			}
+		})
+	}
+}
+
+func TestRotation_CheckDisabled(t *testing.T) {
+	rotation := &Rotation{Previous: "Xk9mP2vLqrwz"}
+	if err := rotation.Check("Xk9mP2vLqr4y"); err != nil {
+		t.Errorf("Check() with disabled distance and substring checks failed: %s", err)
+	}
+}
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int64
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"äöü", "aöu", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if distance := levenshteinDistance(tt.a, tt.b); distance != tt.want {
+				t.Errorf("levenshteinDistance() failed, expected: %d, got: %d", tt.want, distance)
+			}
+		})
+	}
+}
+
+func TestLongestCommonSubstring(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int64
+	}{
+		{"", "abc", 0},
+		{"abc", "xyz", 0},
+		{"abcdef", "zcdez", 3},
+		{"password", "password", 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if length := longestCommonSubstring(tt.a, tt.b); length != tt.want {
+				t.Errorf("longestCommonSubstring() failed, expected: %d, got: %d", tt.want, length)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateRotation(t *testing.T) {
+	rotation := NewRotation("Xk9mP2vLqrwz")
+	generator := New(NewConfig(WithAlgorithm(AlgoRandom), WithRotation(rotation)))
+	for range 100 {
+		password, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Generate() failed: %s", err)
+		}
+		if err = rotation.Check(password); err != nil {
+			t.Errorf("Generate() failed, password does not pass the rotation check: %s", err)
+		}
+	}
+}
+
+func TestGenerator_GenerateFilterAttemptsExceeded(t *testing.T) {
+	errFilter := errors.New("filtered")
+	generator := New(NewConfig(WithAlgorithm(AlgoCoinFlip), WithFilters(func(string) error {
+		return errFilter
+	})))
+	_, err := generator.Generate()
+	if !errors.Is(err, ErrFilterAttemptsExceeded) {
+		t.Errorf("Generate() failed, expected error: %s, got: %s", ErrFilterAttemptsExceeded, err)
+	}
+	if !errors.Is(err, errFilter) {
+		t.Errorf("Generate() failed, expected error to wrap the filter error: %s", err)
+	}
+}
@@ -0,0 +1,310 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SettingsEnvPrefix is the prefix of the environment variables that hold Settings.
+// The name of the variable is the prefix followed by the upper-case key of the
+// setting, i. e. APG_MIN_LENGTH
+const SettingsEnvPrefix = "APG_"
+
+var (
+	// ErrInvalidSetting is returned if the value of a setting can not be parsed or
+	// applied
+	ErrInvalidSetting = errors.New("invalid setting")
+	// ErrUnknownSetting is returned if a settings file holds a key that is not known
+	ErrUnknownSetting = errors.New("unknown setting")
+)
+
+// Settings represents the serializable subset of the Config settings, as used in
+// configuration files and environment variables. Every setting is optional: only
+// the settings that are not nil are applied to a Config. The keys of the settings
+// are given by the toml tags of the fields. Character modes use the flags syntax
+// of ModesFromFlags (i. e. "LUNS") and the other enumerations use the names that
+// are accepted by the corresponding FromString functions
+type Settings struct {
+	ADComplexity       *bool      `toml:"ad_complexity" json:"ad_complexity,omitempty"`
+	Algorithm          *Algorithm `toml:"algorithm" json:"algorithm,omitempty"`
+	BinaryHexMode      *bool      `toml:"binary_hex_mode" json:"binary_hex_mode,omitempty"`
+	BinaryNewline      *bool      `toml:"binary_newline" json:"binary_newline,omitempty"`
+	Capitalization     *string    `toml:"capitalization" json:"capitalization,omitempty"`
+	CheckCharGroupSize *int64     `toml:"check_char_group_size" json:"check_char_group_size,omitempty"`
+	CheckHIBP          *bool      `toml:"check_hibp" json:"check_hibp,omitempty"`
+	Confusables        *string    `toml:"confusables" json:"confusables,omitempty"`
+	ExcludeChars       *string    `toml:"exclude_chars" json:"exclude_chars,omitempty"`
+	FirstCharMode      *ModeMask  `toml:"first_char_mode" json:"first_char_mode,omitempty"`
+	FixedLength        *int64     `toml:"fixed_length" json:"fixed_length,omitempty"`
+	ForbiddenWords     []string   `toml:"forbidden_words" json:"forbidden_words,omitempty"`
+	KeyboardLayouts    *string    `toml:"keyboard_layouts" json:"keyboard_layouts,omitempty"`
+	LastCharMode       *ModeMask  `toml:"last_char_mode" json:"last_char_mode,omitempty"`
+	LengthStrategy     *string    `toml:"length_strategy" json:"length_strategy,omitempty"`
+	Leetspeak          *bool      `toml:"leetspeak" json:"leetspeak,omitempty"`
+	MaxClassRepeat     *int64     `toml:"max_class_repeat" json:"max_class_repeat,omitempty"`
+	MaxLength          *int64     `toml:"max_length" json:"max_length,omitempty"`
+	MaxLowerCase       *int64     `toml:"max_lower_case" json:"max_lower_case,omitempty"`
+	MaxNumeric         *int64     `toml:"max_numeric" json:"max_numeric,omitempty"`
+	MaxRepeat          *int64     `toml:"max_repeat" json:"max_repeat,omitempty"`
+	MaxSequence        *int64     `toml:"max_sequence" json:"max_sequence,omitempty"`
+	MaxSpecial         *int64     `toml:"max_special" json:"max_special,omitempty"`
+	MaxUpperCase       *int64     `toml:"max_upper_case" json:"max_upper_case,omitempty"`
+	MinClasses         *int64     `toml:"min_classes" json:"min_classes,omitempty"`
+	MinLength          *int64     `toml:"min_length" json:"min_length,omitempty"`
+	MinLowerCase       *int64     `toml:"min_lower_case" json:"min_lower_case,omitempty"`
+	MinNumeric         *int64     `toml:"min_numeric" json:"min_numeric,omitempty"`
+	MinSpecial         *int64     `toml:"min_special" json:"min_special,omitempty"`
+	MinUpperCase       *int64     `toml:"min_upper_case" json:"min_upper_case,omitempty"`
+	MobileGrouping     *bool      `toml:"mobile_grouping" json:"mobile_grouping,omitempty"`
+	Mode               *ModeMask  `toml:"mode" json:"mode,omitempty"`
+	NumberPass         *int64     `toml:"number" json:"number,omitempty"`
+	PasswordRules      *string    `toml:"password_rules" json:"password_rules,omitempty"`
+	SafeFor            *string    `toml:"safe_for" json:"safe_for,omitempty"`
+	SpellPassword      *bool      `toml:"spell_password" json:"spell_password,omitempty"`
+	SpellPronounceable *bool      `toml:"spell_pronounceable" json:"spell_pronounceable,omitempty"`
+	SpellingAlphabet   *string    `toml:"spelling_alphabet" json:"spelling_alphabet,omitempty"`
+	SpellingRenderer   *string    `toml:"spelling_renderer" json:"spelling_renderer,omitempty"`
+	SyllableSeparator  *string    `toml:"syllable_separator" json:"syllable_separator,omitempty"`
+	SyllableSet        *string    `toml:"syllable_set" json:"syllable_set,omitempty"`
+	UniqueChars        *bool      `toml:"unique_chars" json:"unique_chars,omitempty"`
+}
+
+// LoadSettingsFile reads the Settings from the file at the given path. Files with a
+// ".json" extension are read as JSON, any other file is read as TOML. Unknown keys
+// are returned as error, so that typos do not go unnoticed
+func LoadSettingsFile(path string) (*Settings, error) {
+	settings := &Settings{}
+	if err := decodeSettingsFile(path, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SettingsFromEnv returns the Settings of the given environment, as returned by
+// os.Environ. Only the variables with the SettingsEnvPrefix and the key of a
+// setting are used, all other variables are ignored. Boolean values are parsed
+// with strconv.ParseBool and lists (forbidden_words) are comma-separated
+func SettingsFromEnv(environ []string) (*Settings, error) {
+	settings := &Settings{}
+	fields := settingsFields()
+	for _, variable := range environ {
+		name, value, ok := strings.Cut(variable, "=")
+		if !ok || !strings.HasPrefix(name, SettingsEnvPrefix) {
+			continue
+		}
+		index, ok := fields[strings.ToLower(strings.TrimPrefix(name, SettingsEnvPrefix))]
+		if !ok {
+			continue
+		}
+		if err := setSettingsField(reflect.ValueOf(settings).Elem().Field(index), value); err != nil {
+			return nil, fmt.Errorf("%w %s: %s", ErrInvalidSetting, name, err)
+		}
+	}
+	return settings, nil
+}
+
+// Apply applies all settings that are not nil to the given Config. An error is
+// returned if a setting holds an invalid value
+func (s *Settings) Apply(config *Config) error {
+	applyValue(s.Algorithm, &config.Algorithm)
+	applyValue(s.BinaryHexMode, &config.BinaryHexMode)
+	applyValue(s.BinaryNewline, &config.BinaryNewline)
+	applyValue(s.CheckCharGroupSize, &config.CheckCharGroupSize)
+	applyValue(s.CheckHIBP, &config.CheckHIBP)
+	applyValue(s.ExcludeChars, &config.ExcludeChars)
+	applyValue(s.FirstCharMode, &config.FirstCharMode)
+	applyValue(s.FixedLength, &config.FixedLength)
+	applyValue(s.LastCharMode, &config.LastCharMode)
+	applyValue(s.Leetspeak, &config.Leetspeak)
+	applyValue(s.MaxClassRepeat, &config.MaxClassRepeat)
+	applyValue(s.MaxLength, &config.MaxLength)
+	applyValue(s.MaxLowerCase, &config.MaxLowerCase)
+	applyValue(s.MaxNumeric, &config.MaxNumeric)
+	applyValue(s.MaxRepeat, &config.MaxRepeat)
+	applyValue(s.MaxSequence, &config.MaxSequence)
+	applyValue(s.MaxSpecial, &config.MaxSpecial)
+	applyValue(s.MaxUpperCase, &config.MaxUpperCase)
+	applyValue(s.MinClasses, &config.MinClasses)
+	applyValue(s.MinLength, &config.MinLength)
+	applyValue(s.MinLowerCase, &config.MinLowerCase)
+	applyValue(s.MinNumeric, &config.MinNumeric)
+	applyValue(s.MinSpecial, &config.MinSpecial)
+	applyValue(s.MinUpperCase, &config.MinUpperCase)
+	applyValue(s.MobileGrouping, &config.MobileGrouping)
+	applyValue(s.Mode, &config.Mode)
+	applyValue(s.NumberPass, &config.NumberPass)
+	applyValue(s.SpellPassword, &config.SpellPassword)
+	applyValue(s.SpellPronounceable, &config.SpellPronounceable)
+	applyValue(s.SyllableSeparator, &config.SyllableSeparator)
+	applyValue(s.UniqueChars, &config.UniqueChars)
+	if s.ForbiddenWords != nil {
+		config.ForbiddenWords = append([]string{}, s.ForbiddenWords...)
+	}
+	if s.ADComplexity != nil && *s.ADComplexity {
+		WithADComplexity("", "")(config)
+	}
+
+	// Settings that have to be parsed
+	var err error
+	if s.Capitalization != nil {
+		if config.Capitalization, err = CapitalizationStyleFromString(*s.Capitalization); err != nil {
+			return fmt.Errorf("%w capitalization: %w", ErrInvalidSetting, err)
+		}
+	}
+	if s.Confusables != nil {
+		mask, err := ConfusablesFromString(*s.Confusables)
+		if err != nil {
+			return fmt.Errorf("%w confusables: %w", ErrInvalidSetting, err)
+		}
+		WithConfusables(mask)(config)
+	}
+	if s.KeyboardLayouts != nil {
+		layouts, err := KeyboardLayoutsFromString(*s.KeyboardLayouts)
+		if err != nil {
+			return fmt.Errorf("%w keyboard_layouts: %w", ErrInvalidSetting, err)
+		}
+		WithKeyboardLayouts(layouts...)(config)
+	}
+	if s.LengthStrategy != nil {
+		if config.LengthStrategy, err = LengthStrategyFromString(*s.LengthStrategy); err != nil {
+			return fmt.Errorf("%w length_strategy: %w", ErrInvalidSetting, err)
+		}
+	}
+	if s.PasswordRules != nil {
+		rules, err := ParsePasswordRules(*s.PasswordRules)
+		if err != nil {
+			return fmt.Errorf("%w password_rules: %w", ErrInvalidSetting, err)
+		}
+		WithPasswordRules(rules)(config)
+	}
+	if s.SafeFor != nil {
+		if config.SafeFor, err = EscapeContextFromString(*s.SafeFor); err != nil {
+			return fmt.Errorf("%w safe_for: %w", ErrInvalidSetting, err)
+		}
+	}
+	if s.SpellingAlphabet != nil {
+		if config.SpellingAlphabet, err = SpellingAlphabetByName(*s.SpellingAlphabet); err != nil {
+			return fmt.Errorf("%w spelling_alphabet: %w", ErrInvalidSetting, err)
+		}
+	}
+	if s.SpellingRenderer != nil {
+		if config.SpellingRenderer, err = SpellingRendererFromString(*s.SpellingRenderer); err != nil {
+			return fmt.Errorf("%w spelling_renderer: %w", ErrInvalidSetting, err)
+		}
+	}
+	if s.SyllableSet != nil {
+		set, err := SyllableSetByName(*s.SyllableSet)
+		if errors.Is(err, ErrUnknownSyllableSet) {
+			set, err = LoadSyllableSet(*s.SyllableSet)
+		}
+		if err != nil {
+			return fmt.Errorf("%w syllable_set: %w", ErrInvalidSetting, err)
+		}
+		config.SyllableSet = set
+	}
+	return nil
+}
+
+// decodeSettingsFile decodes the JSON or TOML file at the given path into the given
+// target. Unknown keys are returned as ErrUnknownSetting
+func decodeSettingsFile(path string, target any) error {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open settings file: %w", err)
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(target); err != nil {
+			if strings.HasPrefix(err.Error(), "json: unknown field") {
+				return fmt.Errorf("%w in %s: %s", ErrUnknownSetting, path,
+					strings.TrimPrefix(err.Error(), "json: unknown field "))
+			}
+			return fmt.Errorf("failed to parse settings file %s: %w", path, err)
+		}
+		return nil
+	}
+
+	metadata, err := toml.DecodeFile(path, target)
+	if err != nil {
+		return fmt.Errorf("failed to parse settings file %s: %w", path, err)
+	}
+	if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("%w in %s: %s", ErrUnknownSetting, path, undecoded[0])
+	}
+	return nil
+}
+
+// applyValue sets the target to the value of the given setting, if it is not nil
+func applyValue[T any](setting *T, target *T) {
+	if setting != nil {
+		*target = *setting
+	}
+}
+
+// settingsFields returns the field indices of the Settings type by their keys
+func settingsFields() map[string]int {
+	fields := make(map[string]int)
+	settingsType := reflect.TypeOf(Settings{})
+	for i := 0; i < settingsType.NumField(); i++ {
+		fields[settingsType.Field(i).Tag.Get("toml")] = i
+	}
+	return fields
+}
+
+// setSettingsField parses the given value into the given field of a Settings value
+func setSettingsField(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Slice {
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+		return nil
+	}
+
+	target := reflect.New(field.Type().Elem())
+	if unmarshaler, ok := target.Interface().(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+			return err
+		}
+		field.Set(target)
+		return nil
+	}
+	switch target.Elem().Kind() {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		target.Elem().SetBool(parsed)
+	case reflect.Int64:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return err
+		}
+		target.Elem().SetInt(parsed)
+	default:
+		target.Elem().SetString(value)
+	}
+	field.Set(target)
+	return nil
+}
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testSettingsTOML = `algorithm = "random"
+mode = "LUNS"
+min_length = 16
+max_length = 24
+exclude_chars = "\"'\\"
+forbidden_words = ["company", "winter"]
+safe_for = "sh"
+spelling_alphabet = "din5009"
+`
+
+const testSettingsJSON = `{
+  "algorithm": "pronounceable",
+  "number": 3,
+  "syllable_separator": "-",
+  "capitalization": "none",
+  "first_char_mode": "L"
+}`
+
+func TestLoadSettingsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte(testSettingsTOML), 0o600); err != nil {
+		t.Fatalf("failed to write settings file: %s", err)
+	}
+	settings, err := LoadSettingsFile(path)
+	if err != nil {
+		t.Fatalf("LoadSettingsFile() failed: %s", err)
+	}
+	config := NewConfig()
+	if err = settings.Apply(config); err != nil {
+		t.Fatalf("Settings.Apply() failed: %s", err)
+	}
+	if config.Algorithm != AlgoRandom {
+		t.Errorf("LoadSettingsFile() failed, expected algorithm: %s, got: %s", AlgoRandom, config.Algorithm)
+	}
+	if want := ModeMask(ModeLowerCase | ModeNumeric | ModeSpecial | ModeUpperCase); config.Mode != want {
+		t.Errorf("LoadSettingsFile() failed, expected mode: %d, got: %d", want, config.Mode)
+	}
+	if config.MinLength != 16 || config.MaxLength != 24 {
+		t.Errorf("LoadSettingsFile() failed, expected length: 16-24, got: %d-%d", config.MinLength,
+			config.MaxLength)
+	}
+	if config.ExcludeChars != `"'\` {
+		t.Errorf("LoadSettingsFile() failed, expected exclude chars: %s, got: %s", `"'\`, config.ExcludeChars)
+	}
+	if len(config.ForbiddenWords) != 2 || config.ForbiddenWords[1] != "winter" {
+		t.Errorf("LoadSettingsFile() failed, expected forbidden words: %v, got: %v",
+			[]string{"company", "winter"}, config.ForbiddenWords)
+	}
+	if config.SafeFor != EscapeShell {
+		t.Errorf("LoadSettingsFile() failed, expected safe for: %s, got: %s", EscapeShell, config.SafeFor)
+	}
+	if config.SpellingAlphabet.Name != SpellingAlphabetDIN5009.Name {
+		t.Errorf("LoadSettingsFile() failed, expected spelling alphabet: %s, got: %s",
+			SpellingAlphabetDIN5009.Name, config.SpellingAlphabet.Name)
+	}
+	if config.NumberPass != DefaultNumberPass {
+		t.Errorf("LoadSettingsFile() failed, unset number expected: %d, got: %d", DefaultNumberPass,
+			config.NumberPass)
+	}
+}
+
+func TestLoadSettingsFile_json(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(testSettingsJSON), 0o600); err != nil {
+		t.Fatalf("failed to write settings file: %s", err)
+	}
+	settings, err := LoadSettingsFile(path)
+	if err != nil {
+		t.Fatalf("LoadSettingsFile() failed: %s", err)
+	}
+	config := NewConfig()
+	if err = settings.Apply(config); err != nil {
+		t.Fatalf("Settings.Apply() failed: %s", err)
+	}
+	if config.Algorithm != AlgoPronounceable {
+		t.Errorf("LoadSettingsFile() failed, expected algorithm: %s, got: %s", AlgoPronounceable,
+			config.Algorithm)
+	}
+	if config.NumberPass != 3 {
+		t.Errorf("LoadSettingsFile() failed, expected number: %d, got: %d", 3, config.NumberPass)
+	}
+	if config.SyllableSeparator != "-" {
+		t.Errorf("LoadSettingsFile() failed, expected separator: %s, got: %s", "-", config.SyllableSeparator)
+	}
+	if config.Capitalization != CapitalizationNone {
+		t.Errorf("LoadSettingsFile() failed, expected capitalization: %d, got: %d", CapitalizationNone,
+			config.Capitalization)
+	}
+	if config.FirstCharMode != ModeLowerCase {
+		t.Errorf("LoadSettingsFile() failed, expected first char mode: %d, got: %d", ModeLowerCase,
+			config.FirstCharMode)
+	}
+}
+
+func TestLoadSettingsFile_fails(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr error
+	}{
+		{"unknown TOML key", "config.toml", "min_lenght = 12\n", ErrUnknownSetting},
+		{"unknown JSON key", "config.json", `{"min_lenght": 12}`, ErrUnknownSetting},
+		{"invalid algorithm", "config.toml", "algorithm = \"dice\"\n", nil},
+		{"invalid mode", "config.json", `{"mode": "LUX"}`, nil},
+		{"invalid TOML", "config.toml", "min_length = \n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write settings file: %s", err)
+			}
+			_, err := LoadSettingsFile(path)
+			if err == nil {
+				t.Fatal("LoadSettingsFile() was supposed to fail")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("LoadSettingsFile() failed, expected error: %s, got: %s", tt.wantErr, err)
+			}
+		})
+	}
+	if _, err := LoadSettingsFile(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Error("LoadSettingsFile() with missing file was supposed to fail")
+	}
+}
+
+func TestSettingsFromEnv(t *testing.T) {
+	settings, err := SettingsFromEnv([]string{
+		"APG_ALGORITHM=0", "APG_MODE=LN", "APG_NUMBER=2", "APG_UNIQUE_CHARS=true",
+		"APG_FORBIDDEN_WORDS=foo, bar", "APG_PREVIOUS_PASSWORD=secret", "HOME=/root", "APG_NOVALUE",
+	})
+	if err != nil {
+		t.Fatalf("SettingsFromEnv() failed: %s", err)
+	}
+	config := NewConfig(WithAlgorithm(AlgoRandom))
+	if err = settings.Apply(config); err != nil {
+		t.Fatalf("Settings.Apply() failed: %s", err)
+	}
+	if config.Algorithm != AlgoPronounceable {
+		t.Errorf("SettingsFromEnv() failed, expected algorithm: %s, got: %s", AlgoPronounceable,
+			config.Algorithm)
+	}
+	if want := ModeMask(ModeLowerCase | ModeNumeric); config.Mode != want {
+		t.Errorf("SettingsFromEnv() failed, expected mode: %d, got: %d", want, config.Mode)
+	}
+	if config.NumberPass != 2 || !config.UniqueChars {
+		t.Errorf("SettingsFromEnv() failed, expected number 2 and unique chars, got: %d and %t",
+			config.NumberPass, config.UniqueChars)
+	}
+	if len(config.ForbiddenWords) != 2 || config.ForbiddenWords[1] != "bar" {
+		t.Errorf("SettingsFromEnv() failed, expected forbidden words: %v, got: %v",
+			[]string{"foo", "bar"}, config.ForbiddenWords)
+	}
+	if config.MinLength != DefaultMinLength {
+		t.Errorf("SettingsFromEnv() failed, unset min length expected: %d, got: %d", DefaultMinLength,
+			config.MinLength)
+	}
+
+	for _, variable := range []string{"APG_MIN_LENGTH=twelve", "APG_CHECK_HIBP=maybe", "APG_MODE=X"} {
+		if _, err = SettingsFromEnv([]string{variable}); !errors.Is(err, ErrInvalidSetting) {
+			t.Errorf("SettingsFromEnv(%s) expected ErrInvalidSetting, got: %v", variable, err)
+		}
+	}
+}
+
+func TestSettings_Apply_fails(t *testing.T) {
+	invalid := "invalid"
+	tests := []struct {
+		name     string
+		settings Settings
+	}{
+		{"capitalization", Settings{Capitalization: &invalid}},
+		{"confusables", Settings{Confusables: &invalid}},
+		{"keyboard_layouts", Settings{KeyboardLayouts: &invalid}},
+		{"length_strategy", Settings{LengthStrategy: &invalid}},
+		{"password_rules", Settings{PasswordRules: &invalid}},
+		{"safe_for", Settings{SafeFor: &invalid}},
+		{"spelling_alphabet", Settings{SpellingAlphabet: &invalid}},
+		{"spelling_renderer", Settings{SpellingRenderer: &invalid}},
+		{"syllable_set", Settings{SyllableSet: &invalid}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.settings.Apply(NewConfig()); !errors.Is(err, ErrInvalidSetting) {
+				t.Errorf("Settings.Apply() expected ErrInvalidSetting, got: %v", err)
+			}
+		})
+	}
+}
@@ -7,10 +7,11 @@ package apg
 import (
 	"fmt"
 	"strings"
+	"unicode"
 )
 
 var (
-	symbNumNames = map[byte]string{
+	symbNumNames = map[rune]string{
 		'1': "ONE",
 		'2': "TWO",
 		'3': "THREE",
@@ -54,7 +55,7 @@ var (
 		125: "RIGHT_BRACE",
 		126: "TILDE",
 	}
-	alphabetNames = map[byte]string{
+	alphabetNames = map[rune]string{
 		'A': "Alfa",
 		'B': "Bravo",
 		'C': "Charlie",
@@ -86,38 +87,35 @@ var (
 
 // Spell returns a given string as spelled english phonetic alphabet string
 func Spell(input string) (string, error) {
-	var returnString []string
-	for _, curChar := range input {
-		curSpellString, err := ConvertByteToWord(byte(curChar))
-		if err != nil {
-			return "", err
-		}
-		returnString = append(returnString, curSpellString)
-	}
-	return strings.Join(returnString, "/"), nil
+	return SpellingAlphabetNATO.Spell(input)
+}
+
+// Unspell returns the original string of a given string that is spelled in the
+// english phonetic alphabet, as returned by Spell
+func Unspell(input string) (string, error) {
+	return SpellingAlphabetNATO.Unspell(input)
 }
 
 // Pronounce returns last generated pronounceable password as spelled syllables string
 func (g *Generator) Pronounce() (string, error) {
 	var returnString []string
 	for _, syllable := range g.syllables {
-		isKoremutake := false
-		for _, x := range KoremutakeSyllables {
-			if x == strings.ToLower(syllable) {
-				isKoremutake = true
-			}
-		}
-
-		if isKoremutake {
+		if g.syllableSet().Contains(syllable) {
 			returnString = append(returnString, syllable)
 			continue
 		}
 
-		curSpellString, err := ConvertByteToWord(syllable[0])
-		if err != nil {
-			return "", err
+		// Characters and syllables with leetspeak substitutions are spelled
+		// character by character
+		spelled := make([]string, 0, len(syllable))
+		for _, curChar := range syllable {
+			curSpellString, err := g.spellingAlphabet().Word(curChar)
+			if err != nil {
+				return "", err
+			}
+			spelled = append(spelled, curSpellString)
 		}
-		returnString = append(returnString, curSpellString)
+		returnString = append(returnString, strings.Join(spelled, "/"))
 	}
 	return strings.Join(returnString, "-"), nil
 }
@@ -125,18 +123,8 @@ func (g *Generator) Pronounce() (string, error) {
 // ConvertByteToWord converts a given ASCII byte into the corresponding spelled version
 // of the english phonetic alphabet
 func ConvertByteToWord(charByte byte) (string, error) {
-	var returnString string
-	switch {
-	case charByte > 64 && charByte < 91:
-		returnString = alphabetNames[charByte]
-	case charByte > 96 && charByte < 123:
-		returnString = strings.ToLower(alphabetNames[charByte-32])
-	default:
-		returnString = symbNumNames[charByte]
-	}
-
-	if returnString == "" {
-		return "", fmt.Errorf("failed to convert given byte to word: %s is unsupported", string(charByte))
+	if charByte > unicode.MaxASCII {
+		return "", fmt.Errorf("failed to convert given byte to word: %q is not an ASCII character", charByte)
 	}
-	return returnString, nil
+	return SpellingAlphabetNATO.Word(rune(charByte))
 }
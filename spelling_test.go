@@ -93,9 +93,15 @@ func TestSpell(t *testing.T) {
 			want:    "alfa/ONE",
 			wantErr: false,
 		},
+		{
+			name:    "non-ASCII characters",
+			input:   "üÄöß€",
+			want:    "uniform_DIAERESIS/Alfa_DIAERESIS/oscar_DIAERESIS/LATIN_SMALL_LETTER_SHARP_S/EURO_SIGN",
+			wantErr: false,
+		},
 		{
 			name:    "not supported characters",
-			input:   "üäöß€",
+			input:   "a\tb",
 			want:    "",
 			wantErr: true,
 		},
@@ -115,6 +121,38 @@ func TestSpell(t *testing.T) {
 	}
 }
 
+func TestUnspell(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"empty string", "", "", false},
+		{"slash separated", "alfa/Bravo/ONE/EXCLAMATION_POINT", "aB1!", false},
+		{"whitespace separated", " alfa  Bravo\tONE ", "aB1", false},
+		{"mixed separators", "alfa/ Bravo /ONE", "aB1", false},
+		{"alpha", "alpha/Alpha", "aA", false},
+		{"juliet", "juliet/Juliett", "jJ", false},
+		{"x-ray", "x-ray/X_ray/XRAY", "xXX", false},
+		{"case-insensitive symbols", "one/Plus_Sign", "1+", false},
+		{"single typo", "Foxtrott/novembr", "Fn", false},
+		{"unknown word", "alfa/foo", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unspell(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Unspell() error = %v, wantErr %t", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Unspell() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPronounce(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -146,9 +184,20 @@ func TestPronounce(t *testing.T) {
 			want:      "mu-ONE",
 			wantErr:   false,
 		},
+		{
+			name:      "Pronounce_Leetspeak",
+			syllables: []string{"mu", "8r0"},
+			want:      "mu-EIGHT/romeo/ZERO",
+			wantErr:   false,
+		},
 		{
 			name:      "Pronounce_NonKoremutakeSyllable",
 			syllables: []string{"ä"},
+			want:      "alfa_DIAERESIS",
+		},
+		{
+			name:      "Pronounce_UnsupportedChar",
+			syllables: []string{"\x00"},
 			wantErr:   true,
 		},
 	}
@@ -0,0 +1,467 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SpellingAlphabet represents a named spelling alphabet that is used to spell
+// passwords. The zero value spells in the NATO alphabet
+type SpellingAlphabet struct {
+	// Name is the name of the spelling alphabet
+	Name string
+	// Letters maps upper-case letters to their code words. Lower-case letters are
+	// spelled with the lower-cased code word of the corresponding upper-case letter
+	Letters map[rune]string
+	// Symbols maps digits and special characters to their names
+	Symbols map[rune]string
+	// Aliases maps alternative and commonly misspelled names to their character.
+	// Aliases are only used to convert names back into characters and aliases of
+	// letters map to the upper-case letter
+	Aliases map[string]rune
+}
+
+var (
+	// ErrInvalidSpellingAlphabet is returned if a spelling alphabet can not be used
+	// to spell passwords
+	ErrInvalidSpellingAlphabet = errors.New("invalid spelling alphabet")
+	// ErrUnknownSpellingAlphabet is returned if a spelling alphabet name is not known
+	ErrUnknownSpellingAlphabet = errors.New("unknown spelling alphabet")
+	// ErrUnknownSpellingWord is returned if a word can not be converted back into a
+	// character of a spelling alphabet
+	ErrUnknownSpellingWord = errors.New("unknown spelling word")
+)
+
+var (
+	// SpellingAlphabetNATO is the default NATO/ICAO spelling alphabet with English
+	// digit and symbol names
+	SpellingAlphabetNATO = SpellingAlphabet{Name: "nato", Letters: alphabetNames, Symbols: symbNumNames,
+		Aliases: englishAliases}
+
+	// SpellingAlphabetAPCO is the APCO spelling alphabet that is used by the police
+	// in the US, with English digit and symbol names
+	SpellingAlphabetAPCO = SpellingAlphabet{Name: "apco", Letters: map[rune]string{
+		'A': "Adam", 'B': "Boy", 'C': "Charles", 'D': "David", 'E': "Edward", 'F': "Frank",
+		'G': "George", 'H': "Henry", 'I': "Ida", 'J': "John", 'K': "King", 'L': "Lincoln",
+		'M': "Mary", 'N': "Nora", 'O': "Ocean", 'P': "Peter", 'Q': "Queen", 'R': "Robert",
+		'S': "Sam", 'T': "Tom", 'U': "Union", 'V': "Victor", 'W': "William", 'X': "X_ray",
+		'Y': "Young", 'Z': "Zebra",
+	}, Symbols: symbNumNames, Aliases: englishAliases}
+
+	// SpellingAlphabetDIN5009 is the German spelling alphabet of DIN 5009 with
+	// German digit and symbol names
+	SpellingAlphabetDIN5009 = SpellingAlphabet{Name: "din5009", Letters: map[rune]string{
+		'A': "Anton", 'B': "Berta", 'C': "Cäsar", 'D': "Dora", 'E': "Emil", 'F': "Friedrich",
+		'G': "Gustav", 'H': "Heinrich", 'I': "Ida", 'J': "Julius", 'K': "Kaufmann",
+		'L': "Ludwig", 'M': "Martha", 'N': "Nordpol", 'O': "Otto", 'P': "Paula", 'Q': "Quelle",
+		'R': "Richard", 'S': "Samuel", 'T': "Theodor", 'U': "Ulrich", 'V': "Viktor",
+		'W': "Wilhelm", 'X': "Xanthippe", 'Y': "Ypsilon", 'Z': "Zacharias", 'Ä': "Ärger",
+		'Ö': "Ökonom", 'Ü': "Übermut", 'ß': "Eszett",
+	}, Symbols: map[rune]string{
+		'1': "EINS", '2': "ZWEI", '3': "DREI", '4': "VIER", '5': "FÜNF", '6': "SECHS",
+		'7': "SIEBEN", '8': "ACHT", '9': "NEUN", '0': "NULL",
+		'!': "AUSRUFEZEICHEN", '"': "ANFÜHRUNGSZEICHEN", '#': "RAUTE", '$': "DOLLARZEICHEN",
+		'%': "PROZENTZEICHEN", '&': "KAUFMANNS_UND", '\'': "APOSTROPH",
+		'(': "RUNDE_KLAMMER_AUF", ')': "RUNDE_KLAMMER_ZU", '*': "STERN", '+': "PLUS",
+		',': "KOMMA", '-': "BINDESTRICH", '.': "PUNKT", '/': "SCHRÄGSTRICH",
+		':': "DOPPELPUNKT", ';': "SEMIKOLON", '<': "KLEINER_ALS", '=': "GLEICHHEITSZEICHEN",
+		'>': "GRÖSSER_ALS", '?': "FRAGEZEICHEN", '@': "KLAMMERAFFE",
+		'[': "ECKIGE_KLAMMER_AUF", '\\': "RÜCKSTRICH", ']': "ECKIGE_KLAMMER_ZU",
+		'^': "ZIRKUMFLEX", '_': "UNTERSTRICH", '`': "GRAVIS",
+		'{': "GESCHWEIFTE_KLAMMER_AUF", '|': "SENKRECHTER_STRICH",
+		'}': "GESCHWEIFTE_KLAMMER_ZU", '~': "TILDE",
+	}, Aliases: map[string]rune{
+		"Caesar": 'C', "Siegfried": 'S', "Zeppelin": 'Z', "Aerger": 'Ä', "Oekonom": 'Ö',
+		"Uebermut": 'Ü', "ZWO": '2', "RAUTEZEICHEN": '#', "SCHRAEGSTRICH": '/',
+		"GROESSER_ALS": '>', "AT": '@', "MINUS": '-', "BACKSLASH": '\\',
+	}}
+
+	// SpellingAlphabetDutch is the Dutch spelling alphabet with Dutch digit and
+	// symbol names
+	SpellingAlphabetDutch = SpellingAlphabet{Name: "nl", Letters: map[rune]string{
+		'A': "Anton", 'B': "Bernard", 'C': "Cornelis", 'D': "Dirk", 'E': "Eduard",
+		'F': "Ferdinand", 'G': "Gerard", 'H': "Hendrik", 'I': "Izaak", 'J': "Johan",
+		'K': "Karel", 'L': "Lodewijk", 'M': "Maria", 'N': "Nico", 'O': "Otto", 'P': "Pieter",
+		'Q': "Quotiënt", 'R': "Richard", 'S': "Simon", 'T': "Teunis", 'U': "Utrecht",
+		'V': "Victor", 'W': "Willem", 'X': "Xantippe", 'Y': "Ypsilon", 'Z': "Zaandam",
+	}, Symbols: map[rune]string{
+		'1': "EEN", '2': "TWEE", '3': "DRIE", '4': "VIER", '5': "VIJF", '6': "ZES",
+		'7': "ZEVEN", '8': "ACHT", '9': "NEGEN", '0': "NUL",
+		'!': "UITROEPTEKEN", '"': "AANHALINGSTEKEN", '#': "HEKJE", '$': "DOLLARTEKEN",
+		'%': "PROCENTTEKEN", '&': "EN_TEKEN", '\'': "APOSTROF", '(': "HAAKJE_OPENEN",
+		')': "HAAKJE_SLUITEN", '*': "STERRETJE", '+': "PLUSTEKEN", ',': "KOMMA",
+		'-': "STREEPJE", '.': "PUNT", '/': "SCHUINE_STREEP", ':': "DUBBELE_PUNT",
+		';': "PUNTKOMMA", '<': "KLEINER_DAN", '=': "ISTEKEN", '>': "GROTER_DAN",
+		'?': "VRAAGTEKEN", '@': "APENSTAARTJE", '[': "VIERKANT_HAAKJE_OPENEN",
+		'\\': "BACKSLASH", ']': "VIERKANT_HAAKJE_SLUITEN", '^': "DAKJE",
+		'_': "LAAG_STREEPJE", '`': "ACCENT_GRAVE", '{': "ACCOLADE_OPENEN",
+		'|': "VERTICALE_STREEP", '}': "ACCOLADE_SLUITEN", '~': "TILDE",
+	}}
+
+	// SpellingAlphabetFrench is the French spelling alphabet with French digit and
+	// symbol names
+	SpellingAlphabetFrench = SpellingAlphabet{Name: "fr", Letters: map[rune]string{
+		'A': "Anatole", 'B': "Berthe", 'C': "Célestin", 'D': "Désiré", 'E': "Eugène",
+		'F': "François", 'G': "Gaston", 'H': "Henri", 'I': "Irma", 'J': "Joseph",
+		'K': "Kléber", 'L': "Louis", 'M': "Marcel", 'N': "Nicolas", 'O': "Oscar",
+		'P': "Pierre", 'Q': "Quintal", 'R': "Raoul", 'S': "Suzanne", 'T': "Thérèse",
+		'U': "Ursule", 'V': "Victor", 'W': "William", 'X': "Xavier", 'Y': "Yvonne",
+		'Z': "Zoé",
+	}, Symbols: map[rune]string{
+		'1': "UN", '2': "DEUX", '3': "TROIS", '4': "QUATRE", '5': "CINQ", '6': "SIX",
+		'7': "SEPT", '8': "HUIT", '9': "NEUF", '0': "ZÉRO",
+		'!': "POINT_D_EXCLAMATION", '"': "GUILLEMET", '#': "DIÈSE", '$': "DOLLAR",
+		'%': "POURCENT", '&': "ESPERLUETTE", '\'': "APOSTROPHE",
+		'(': "PARENTHÈSE_OUVRANTE", ')': "PARENTHÈSE_FERMANTE", '*': "ASTÉRISQUE",
+		'+': "PLUS", ',': "VIRGULE", '-': "TRAIT_D_UNION", '.': "POINT",
+		'/': "BARRE_OBLIQUE", ':': "DEUX_POINTS", ';': "POINT_VIRGULE", '<': "INFÉRIEUR",
+		'=': "ÉGAL", '>': "SUPÉRIEUR", '?': "POINT_D_INTERROGATION", '@': "AROBASE",
+		'[': "CROCHET_OUVRANT", '\\': "BARRE_OBLIQUE_INVERSÉE", ']': "CROCHET_FERMANT",
+		'^': "ACCENT_CIRCONFLEXE", '_': "TIRET_BAS", '`': "ACCENT_GRAVE",
+		'{': "ACCOLADE_OUVRANTE", '|': "BARRE_VERTICALE", '}': "ACCOLADE_FERMANTE",
+		'~': "TILDE",
+	}}
+
+	// SpellingAlphabetItalian is the Italian spelling alphabet with Italian digit
+	// and symbol names
+	SpellingAlphabetItalian = SpellingAlphabet{Name: "it", Letters: map[rune]string{
+		'A': "Ancona", 'B': "Bologna", 'C': "Como", 'D': "Domodossola", 'E': "Empoli",
+		'F': "Firenze", 'G': "Genova", 'H': "Hotel", 'I': "Imola", 'J': "Jolly",
+		'K': "Kappa", 'L': "Livorno", 'M': "Milano", 'N': "Napoli", 'O': "Otranto",
+		'P': "Padova", 'Q': "Quarto", 'R': "Roma", 'S': "Savona", 'T': "Torino",
+		'U': "Udine", 'V': "Venezia", 'W': "Washington", 'X': "Xeres", 'Y': "York",
+		'Z': "Zara",
+	}, Symbols: map[rune]string{
+		'1': "UNO", '2': "DUE", '3': "TRE", '4': "QUATTRO", '5': "CINQUE", '6': "SEI",
+		'7': "SETTE", '8': "OTTO", '9': "NOVE", '0': "ZERO",
+		'!': "PUNTO_ESCLAMATIVO", '"': "VIRGOLETTE", '#': "CANCELLETTO", '$': "DOLLARO",
+		'%': "PERCENTO", '&': "E_COMMERCIALE", '\'': "APOSTROFO",
+		'(': "PARENTESI_TONDA_APERTA", ')': "PARENTESI_TONDA_CHIUSA", '*': "ASTERISCO",
+		'+': "PIÙ", ',': "VIRGOLA", '-': "TRATTINO", '.': "PUNTO", '/': "BARRA",
+		':': "DUE_PUNTI", ';': "PUNTO_E_VIRGOLA", '<': "MINORE", '=': "UGUALE",
+		'>': "MAGGIORE", '?': "PUNTO_INTERROGATIVO", '@': "CHIOCCIOLA",
+		'[': "PARENTESI_QUADRA_APERTA", '\\': "BARRA_ROVESCIATA",
+		']': "PARENTESI_QUADRA_CHIUSA", '^': "ACCENTO_CIRCONFLESSO", '_': "TRATTINO_BASSO",
+		'`': "ACCENTO_GRAVE", '{': "PARENTESI_GRAFFA_APERTA", '|': "BARRA_VERTICALE",
+		'}': "PARENTESI_GRAFFA_CHIUSA", '~': "TILDE",
+	}}
+
+	// SpellingAlphabetSpanish is the Spanish spelling alphabet with Spanish digit
+	// and symbol names
+	SpellingAlphabetSpanish = SpellingAlphabet{Name: "es", Letters: map[rune]string{
+		'A': "Antonio", 'B': "Barcelona", 'C': "Carmen", 'D': "Dolores", 'E': "Enrique",
+		'F': "Francia", 'G': "Gerona", 'H': "Historia", 'I': "Inés", 'J': "José",
+		'K': "Kilo", 'L': "Lorenzo", 'M': "Madrid", 'N': "Navarra", 'O': "Oviedo",
+		'P': "París", 'Q': "Querido", 'R': "Ramón", 'S': "Sábado", 'T': "Tarragona",
+		'U': "Ulises", 'V': "Valencia", 'W': "Washington", 'X': "Xilófono", 'Y': "Yegua",
+		'Z': "Zaragoza", 'Ñ': "Ñandú",
+	}, Symbols: map[rune]string{
+		'1': "UNO", '2': "DOS", '3': "TRES", '4': "CUATRO", '5': "CINCO", '6': "SEIS",
+		'7': "SIETE", '8': "OCHO", '9': "NUEVE", '0': "CERO",
+		'!': "EXCLAMACIÓN", '"': "COMILLAS", '#': "ALMOHADILLA", '$': "DÓLAR",
+		'%': "PORCENTAJE", '&': "AMPERSAND", '\'': "APÓSTROFO", '(': "ABRE_PARÉNTESIS",
+		')': "CIERRA_PARÉNTESIS", '*': "ASTERISCO", '+': "MÁS", ',': "COMA", '-': "GUION",
+		'.': "PUNTO", '/': "BARRA", ':': "DOS_PUNTOS", ';': "PUNTO_Y_COMA",
+		'<': "MENOR_QUE", '=': "IGUAL", '>': "MAYOR_QUE", '?': "INTERROGACIÓN",
+		'@': "ARROBA", '[': "ABRE_CORCHETE", '\\': "BARRA_INVERTIDA",
+		']': "CIERRA_CORCHETE", '^': "CIRCUNFLEJO", '_': "GUION_BAJO",
+		'`': "ACENTO_GRAVE", '{': "ABRE_LLAVE", '|': "BARRA_VERTICAL", '}': "CIERRA_LLAVE",
+		'~': "TILDE",
+	}}
+
+	// englishAliases holds alternative and commonly misspelled names of the NATO
+	// and APCO alphabets and the English digit and symbol names
+	englishAliases = map[string]rune{
+		"Alpha": 'A', "Juliet": 'J', "Whisky": 'W', "Xray": 'X', "NINER": '9', "TREE": '3',
+		"FOWER": '4', "FIFE": '5', "EXCLAMATION_MARK": '!', "HASH": '#', "POUND": '#',
+		"DOLLAR": '$', "PERCENT": '%', "STAR": '*', "PLUS": '+', "DASH": '-', "MINUS": '-',
+		"DOT": '.', "FULL_STOP": '.', "EQUALS": '=', "AT": '@', "CARET": '^',
+		"BACKTICK": '`', "PIPE": '|',
+	}
+
+	// builtinSpellingAlphabets maps the names and aliases of the built-in spelling
+	// alphabets to the corresponding alphabet
+	builtinSpellingAlphabets = map[string]SpellingAlphabet{
+		"nato":    SpellingAlphabetNATO,
+		"icao":    SpellingAlphabetNATO,
+		"en":      SpellingAlphabetNATO,
+		"apco":    SpellingAlphabetAPCO,
+		"police":  SpellingAlphabetAPCO,
+		"din5009": SpellingAlphabetDIN5009,
+		"de":      SpellingAlphabetDIN5009,
+		"german":  SpellingAlphabetDIN5009,
+		"nl":      SpellingAlphabetDutch,
+		"dutch":   SpellingAlphabetDutch,
+		"fr":      SpellingAlphabetFrench,
+		"french":  SpellingAlphabetFrench,
+		"it":      SpellingAlphabetItalian,
+		"italian": SpellingAlphabetItalian,
+		"es":      SpellingAlphabetSpanish,
+		"spanish": SpellingAlphabetSpanish,
+	}
+
+	// localeSpellingAlphabets maps language codes to the spelling alphabet that is
+	// used for locales of the corresponding language
+	localeSpellingAlphabets = map[string]SpellingAlphabet{
+		"de": SpellingAlphabetDIN5009,
+		"es": SpellingAlphabetSpanish,
+		"fr": SpellingAlphabetFrench,
+		"it": SpellingAlphabetItalian,
+		"nl": SpellingAlphabetDutch,
+	}
+)
+
+// init validates the built-in spelling alphabets
+func init() {
+	for name, alphabet := range builtinSpellingAlphabets {
+		if err := alphabet.Validate(); err != nil {
+			panic(fmt.Sprintf("invalid built-in spelling alphabet %q: %s", name, err))
+		}
+	}
+}
+
+// SpellingAlphabetByName returns the built-in spelling alphabet with the given
+// name. Valid names are "nato" (or "icao" and "en"), "apco" (or "police"),
+// "din5009" (or "de" and "german"), "nl" (or "dutch"), "fr" (or "french"),
+// "it" (or "italian") and "es" (or "spanish")
+func SpellingAlphabetByName(name string) (SpellingAlphabet, error) {
+	alphabet, ok := builtinSpellingAlphabets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return SpellingAlphabet{}, fmt.Errorf("%w: %s", ErrUnknownSpellingAlphabet, name)
+	}
+	return alphabet, nil
+}
+
+// SpellingAlphabetForLocale returns the spelling alphabet for the language of the
+// given POSIX or BCP 47 locale (i. e. "de_DE.UTF-8" or "fr-CH"). Locales of
+// languages without a localized spelling alphabet use the NATO alphabet
+func SpellingAlphabetForLocale(locale string) SpellingAlphabet {
+	language, _, _ := strings.Cut(locale, ".")
+	language, _, _ = strings.Cut(language, "@")
+	language, _, _ = strings.Cut(language, "_")
+	language, _, _ = strings.Cut(language, "-")
+	if alphabet, ok := localeSpellingAlphabets[strings.ToLower(language)]; ok {
+		return alphabet
+	}
+	return SpellingAlphabetNATO
+}
+
+// Validate checks if the spelling alphabet can be used to spell passwords. Every
+// letter and symbol needs a unique, non-empty name without whitespace or slashes,
+// the letters have to be upper-case letters and aliases have to belong to a letter
+// or symbol of the alphabet
+func (a SpellingAlphabet) Validate() error {
+	if len(a.Letters) == 0 && len(a.Symbols) == 0 {
+		return fmt.Errorf("%w: alphabet %q is empty", ErrInvalidSpellingAlphabet, a.Name)
+	}
+	names := make(map[string]rune)
+	checkName := func(char rune, name string) error {
+		if name == "" || strings.ContainsFunc(name, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '/'
+		}) {
+			return fmt.Errorf("%w: invalid name %q for %q", ErrInvalidSpellingAlphabet, name, char)
+		}
+		key := normalizeSpellingWord(name)
+		if other, ok := names[key]; ok && other != char {
+			return fmt.Errorf("%w: name %q is used for %q and %q", ErrInvalidSpellingAlphabet,
+				name, other, char)
+		}
+		names[key] = char
+		return nil
+	}
+	for char, name := range a.Letters {
+		if !unicode.IsLetter(char) || unicode.ToUpper(char) != char {
+			return fmt.Errorf("%w: %q is not an upper-case letter", ErrInvalidSpellingAlphabet, char)
+		}
+		if err := checkName(char, name); err != nil {
+			return err
+		}
+	}
+	for char, name := range a.Symbols {
+		if unicode.IsLetter(char) {
+			return fmt.Errorf("%w: symbol %q is a letter", ErrInvalidSpellingAlphabet, char)
+		}
+		if err := checkName(char, name); err != nil {
+			return err
+		}
+	}
+	for alias, char := range a.Aliases {
+		_, isLetter := a.Letters[char]
+		_, isSymbol := a.Symbols[char]
+		if !isLetter && !isSymbol {
+			return fmt.Errorf("%w: alias %q belongs to unknown character %q", ErrInvalidSpellingAlphabet,
+				alias, char)
+		}
+		if err := checkName(char, alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Spell returns the given string spelled in the spelling alphabet. The names of
+// the characters are separated by a slash
+func (a SpellingAlphabet) Spell(input string) (string, error) {
+	returnString := make([]string, 0, len(input))
+	for _, curChar := range input {
+		curSpellString, err := a.Word(curChar)
+		if err != nil {
+			return "", err
+		}
+		returnString = append(returnString, curSpellString)
+	}
+	return strings.Join(returnString, "/"), nil
+}
+
+// Unspell converts a string spelled in the spelling alphabet back into the original
+// string. The names may be separated by slashes or whitespace. See Char for details
+// on how the names are matched
+func (a SpellingAlphabet) Unspell(input string) (string, error) {
+	words := strings.FieldsFunc(input, func(r rune) bool {
+		return r == '/' || unicode.IsSpace(r)
+	})
+	returnString := strings.Builder{}
+	for _, word := range words {
+		char, err := a.Char(word)
+		if err != nil {
+			return "", err
+		}
+		returnString.WriteRune(char)
+	}
+	return returnString.String(), nil
+}
+
+// Word returns the name of the given character in the spelling alphabet. Letters
+// with diacritical marks that are not part of the alphabet are named after their
+// base letter followed by the names of the marks (i. e. "echo_ACUTE_ACCENT" for
+// "é" in the NATO alphabet). Any other character is named by its Unicode character
+// name (i. e. "EURO_SIGN") or by its code point (i. e. "U+4E00")
+func (a SpellingAlphabet) Word(char rune) (string, error) {
+	a = a.orDefault()
+	upper := unicode.ToUpper(char)
+	if word, ok := a.Letters[upper]; ok {
+		if unicode.IsLower(char) {
+			return strings.ToLower(word), nil
+		}
+		return word, nil
+	}
+	if word, ok := a.Symbols[char]; ok {
+		return word, nil
+	}
+	if word, ok := a.markedLetterWord(char); ok {
+		return word, nil
+	}
+	if word := unicodeCharName(char); word != "" {
+		return word, nil
+	}
+	return "", fmt.Errorf("failed to convert given character to word: %q is unsupported", char)
+}
+
+// Char returns the character for the given name of the spelling alphabet. Names
+// are case-sensitive as returned by Word, so that a lower-case name returns the
+// lower-case letter. If the name does not match exactly, it is matched against the
+// names and aliases of the alphabet ignoring case, accents, hyphens and underscores,
+// then against the names of letters with diacritical marks and Unicode character
+// names as returned by Word and finally against all names that differ by a single
+// character, as long as the match is unambiguous. Letter names without upper-case
+// characters return the lower-case letter
+func (a SpellingAlphabet) Char(word string) (rune, error) {
+	a = a.orDefault()
+	if char, ok := a.exactChar(word); ok {
+		return char, nil
+	}
+
+	names := make(map[string]rune, len(a.Letters)+len(a.Symbols)+len(a.Aliases))
+	for char, name := range a.Letters {
+		names[normalizeSpellingWord(name)] = char
+	}
+	for char, name := range a.Symbols {
+		names[normalizeSpellingWord(name)] = char
+	}
+	for alias, char := range a.Aliases {
+		names[normalizeSpellingWord(alias)] = char
+	}
+	key := normalizeSpellingWord(word)
+	char, ok := names[key]
+	if !ok {
+		if char, ok := a.markedLetterChar(word); ok {
+			return char, nil
+		}
+		if char, ok := unicodeCharByName(word); ok {
+			return char, nil
+		}
+	}
+	if !ok && len([]rune(key)) > 3 {
+		for name, candidate := range names {
+			if levenshteinDistance(key, name) > 1 {
+				continue
+			}
+			if ok && candidate != char {
+				return 0, fmt.Errorf("%w: %q is ambiguous", ErrUnknownSpellingWord, word)
+			}
+			char, ok = candidate, true
+		}
+	}
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownSpellingWord, word)
+	}
+	if _, isLetter := a.Letters[char]; isLetter && !strings.ContainsFunc(word, unicode.IsUpper) {
+		return unicode.ToLower(char), nil
+	}
+	return char, nil
+}
+
+// exactChar returns the character whose name in the spelling alphabet is exactly
+// the given word. Lower-cased letter names return the lower-case letter
+func (a SpellingAlphabet) exactChar(word string) (rune, bool) {
+	for char, name := range a.Letters {
+		switch word {
+		case name:
+			return char, true
+		case strings.ToLower(name):
+			return unicode.ToLower(char), true
+		}
+	}
+	for char, name := range a.Symbols {
+		if word == name {
+			return char, true
+		}
+	}
+	return 0, false
+}
+
+// orDefault returns the NATO alphabet if the spelling alphabet is empty
+func (a SpellingAlphabet) orDefault() SpellingAlphabet {
+	if a.Letters == nil && a.Symbols == nil {
+		return SpellingAlphabetNATO
+	}
+	return a
+}
+
+// spellingAlphabet returns the spelling alphabet of the Generator. If no alphabet
+// is configured, the NATO alphabet is used
+func (g *Generator) spellingAlphabet() SpellingAlphabet {
+	return g.config.SpellingAlphabet.orDefault()
+}
+
+// normalizeSpellingWord returns the lower-cased given name of a spelling alphabet
+// without accents, hyphens and underscores, so that names can be compared
+// independent of their exact spelling
+func normalizeSpellingWord(word string) string {
+	normalized := strings.Builder{}
+	for _, char := range norm.NFD.String(word) {
+		if unicode.Is(unicode.Mn, char) || char == '-' || char == '_' {
+			continue
+		}
+		normalized.WriteRune(unicode.ToLower(char))
+	}
+	return normalized.String()
+}
@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSpellingAlphabets_builtin(t *testing.T) {
+	specials := []rune(CharRangeSpecial)
+	for name, alphabet := range builtinSpellingAlphabets {
+		t.Run(name, func(t *testing.T) {
+			if err := alphabet.Validate(); err != nil {
+				t.Errorf("Validate() failed: %s", err)
+			}
+			for _, char := range []rune(CharRangeAlphaUpper + CharRangeAlphaLower + CharRangeNumeric) {
+				if _, err := alphabet.Word(char); err != nil {
+					t.Errorf("Word() failed for %q: %s", char, err)
+				}
+			}
+			for _, char := range specials {
+				if _, err := alphabet.Word(char); err != nil {
+					t.Errorf("Word() failed for %q: %s", char, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSpellingAlphabetByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"nato", "nato", false},
+		{"ICAO", "nato", false},
+		{"police", "apco", false},
+		{" de ", "din5009", false},
+		{"french", "fr", false},
+		{"it", "it", false},
+		{"spanish", "es", false},
+		{"nl", "nl", false},
+		{"klingon", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alphabet, err := SpellingAlphabetByName(tt.name)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownSpellingAlphabet) {
+					t.Errorf("SpellingAlphabetByName() expected ErrUnknownSpellingAlphabet, got: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("SpellingAlphabetByName() failed: %s", err)
+				return
+			}
+			if alphabet.Name != tt.want {
+				t.Errorf("SpellingAlphabetByName() failed, expected: %s, got: %s", tt.want, alphabet.Name)
+			}
+		})
+	}
+}
+
+func TestSpellingAlphabetForLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"de_DE.UTF-8", "din5009"},
+		{"de_AT", "din5009"},
+		{"fr-CH", "fr"},
+		{"it_IT@euro", "it"},
+		{"es", "es"},
+		{"nl_BE.UTF-8", "nl"},
+		{"en_US.UTF-8", "nato"},
+		{"C", "nato"},
+		{"", "nato"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := SpellingAlphabetForLocale(tt.locale).Name; got != tt.want {
+				t.Errorf("SpellingAlphabetForLocale() failed, expected: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSpellingAlphabet_Spell(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet SpellingAlphabet
+		input    string
+		want     string
+		wantErr  bool
+	}{
+		{"NATO", SpellingAlphabetNATO, "aB1!", "alfa/Bravo/ONE/EXCLAMATION_POINT", false},
+		{"ZeroValue", SpellingAlphabet{}, "aB1", "alfa/Bravo/ONE", false},
+		{"APCO", SpellingAlphabetAPCO, "aB1", "adam/Boy/ONE", false},
+		{"DIN5009", SpellingAlphabetDIN5009, "aBäß1@", "anton/Berta/ärger/eszett/EINS/KLAMMERAFFE", false},
+		{"French", SpellingAlphabetFrench, "cE0#", "célestin/Eugène/ZÉRO/DIÈSE", false},
+		{"Italian", SpellingAlphabetItalian, "yZ8+", "york/Zara/OTTO/PIÙ", false},
+		{"Spanish", SpellingAlphabetSpanish, "ñK5?", "ñandú/Kilo/CINCO/INTERROGACIÓN", false},
+		{"Dutch", SpellingAlphabetDutch, "qW1~", "quotiënt/Willem/EEN/TILDE", false},
+		{"Unsupported", SpellingAlphabetNATO, "\x00", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.alphabet.Spell(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Spell() error = %v, wantErr %t", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Spell() failed, expected: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSpellingAlphabet_Unspell(t *testing.T) {
+	input := CharRangeAlphaUpper + CharRangeAlphaLower + CharRangeNumeric + CharRangeSpecial
+	for name, alphabet := range builtinSpellingAlphabets {
+		t.Run(name, func(t *testing.T) {
+			spelled, err := alphabet.Spell(input)
+			if err != nil {
+				t.Fatalf("Spell() failed: %s", err)
+			}
+			got, err := alphabet.Unspell(spelled)
+			if err != nil {
+				t.Fatalf("Unspell() failed: %s", err)
+			}
+			if got != input {
+				t.Errorf("Unspell() failed, expected: %s, got: %s", input, got)
+			}
+		})
+	}
+}
+
+func TestSpellingAlphabet_Char(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet SpellingAlphabet
+		word     string
+		want     rune
+		wantErr  bool
+	}{
+		{"Exact", SpellingAlphabetDIN5009, "Ärger", 'Ä', false},
+		{"ExactLowerCase", SpellingAlphabetDIN5009, "ärger", 'ä', false},
+		{"WithoutAccents", SpellingAlphabetFrench, "Celestin", 'C', false},
+		{"Alias", SpellingAlphabetDIN5009, "Aerger", 'Ä', false},
+		{"AliasLowerCase", SpellingAlphabetDIN5009, "caesar", 'c', false},
+		{"SymbolAlias", SpellingAlphabetNATO, "niner", '9', false},
+		{"Typo", SpellingAlphabetAPCO, "Lincon", 'L', false},
+		{"ShortTypo", SpellingAlphabetAPCO, "Tim", 0, true},
+		{"Unknown", SpellingAlphabetSpanish, "Alfa", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.alphabet.Char(tt.word)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownSpellingWord) {
+					t.Errorf("Char() expected ErrUnknownSpellingWord, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Char() failed: %s", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Char() failed, expected: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSpellingAlphabet_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet SpellingAlphabet
+	}{
+		{"Empty", SpellingAlphabet{Name: "empty"}},
+		{"LowerCaseLetter", SpellingAlphabet{Letters: map[rune]string{'a': "Alfa"}}},
+		{"SymbolIsLetter", SpellingAlphabet{Symbols: map[rune]string{'a': "ALFA"}}},
+		{"EmptyName", SpellingAlphabet{Letters: map[rune]string{'A': ""}}},
+		{"NameWithSpace", SpellingAlphabet{Letters: map[rune]string{'A': "Al fa"}}},
+		{"NameWithSlash", SpellingAlphabet{Symbols: map[rune]string{'1': "ONE/1"}}},
+		{"UnknownAlias", SpellingAlphabet{
+			Letters: map[rune]string{'A': "Alfa"},
+			Aliases: map[string]rune{"Bravo": 'B'},
+		}},
+		{"DuplicateName", SpellingAlphabet{
+			Letters: map[rune]string{'O': "Otto"},
+			Symbols: map[rune]string{'8': "OTTO"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.alphabet.Validate(); !errors.Is(err, ErrInvalidSpellingAlphabet) {
+				t.Errorf("Validate() expected ErrInvalidSpellingAlphabet, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerator_Pronounce_spellingAlphabet(t *testing.T) {
+	config := NewConfig(WithSpellingAlphabet(SpellingAlphabetDIN5009))
+	generator := New(config)
+	generator.syllables = []string{"ko", "8r0"}
+	got, err := generator.Pronounce()
+	if err != nil {
+		t.Fatalf("Pronounce() failed: %s", err)
+	}
+	if want := "ko-ACHT/richard/NULL"; got != want {
+		t.Errorf("Pronounce() failed, expected: %s, got: %s", want, got)
+	}
+}
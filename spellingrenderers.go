@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SpellingRenderer represents the output format of a spelled password
+type SpellingRenderer uint8
+
+const (
+	// SpellingRendererText renders the names of the characters separated by a slash
+	SpellingRendererText SpellingRenderer = iota
+	// SpellingRendererSSML renders a SSML document for text-to-speech systems. Every
+	// character is read as a character followed by its name, with pauses between
+	// the groups of SSMLGroupSize characters
+	SpellingRendererSSML
+	// SpellingRendererMorse renders the characters in international Morse code. The
+	// Morse code does not distinguish between upper-case and lower-case letters.
+	// Characters without a Morse code are rendered as their name in the spelling
+	// alphabet, enclosed in square brackets
+	SpellingRendererMorse
+	// SpellingRendererJSON renders a JSON array of SpelledChar objects
+	SpellingRendererJSON
+)
+
+// SSMLGroupSize is the amount of characters that are read without a longer pause
+// by the SSML renderer
+const SSMLGroupSize = 4
+
+// ErrUnknownSpellingRenderer is returned if a spelling renderer name is not known
+var ErrUnknownSpellingRenderer = errors.New("unknown spelling renderer")
+
+// morseCodes maps the characters that are part of the international Morse code to
+// their code
+var morseCodes = map[rune]string{
+	'A': ".-", 'B': "-...", 'C': "-.-.", 'D': "-..", 'E': ".", 'F': "..-.", 'G': "--.",
+	'H': "....", 'I': "..", 'J': ".---", 'K': "-.-", 'L': ".-..", 'M': "--", 'N': "-.",
+	'O': "---", 'P': ".--.", 'Q': "--.-", 'R': ".-.", 'S': "...", 'T': "-", 'U': "..-",
+	'V': "...-", 'W': ".--", 'X': "-..-", 'Y': "-.--", 'Z': "--..",
+	'1': ".----", '2': "..---", '3': "...--", '4': "....-", '5': ".....", '6': "-....",
+	'7': "--...", '8': "---..", '9': "----.", '0': "-----",
+	'!': "-.-.--", '"': ".-..-.", '$': "...-..-", '&': ".-...", '\'': ".----.",
+	'(': "-.--.", ')': "-.--.-", '+': ".-.-.", ',': "--..--", '-': "-....-",
+	'.': ".-.-.-", '/': "-..-.", ':': "---...", ';': "-.-.-.", '=': "-...-",
+	'?': "..--..", '@': ".--.-.", '_': "..--.-",
+}
+
+// SpelledChar represents a single character of a spelled password
+type SpelledChar struct {
+	// Char is the spelled character
+	Char string `json:"char"`
+	// Word is the name of the character in the spelling alphabet
+	Word string `json:"word"`
+	// Class is the character class of the character. It is either "lower",
+	// "upper", "numeric" or "special"
+	Class string `json:"class"`
+}
+
+// SpellingRendererFromString returns the SpellingRenderer for the given name. Valid
+// names are "text", "ssml", "morse" and "json"
+func SpellingRendererFromString(renderer string) (SpellingRenderer, error) {
+	switch strings.ToLower(strings.TrimSpace(renderer)) {
+	case "text":
+		return SpellingRendererText, nil
+	case "ssml":
+		return SpellingRendererSSML, nil
+	case "morse":
+		return SpellingRendererMorse, nil
+	case "json":
+		return SpellingRendererJSON, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownSpellingRenderer, renderer)
+	}
+}
+
+// String satisfies the fmt.Stringer interface for the SpellingRenderer type
+func (r SpellingRenderer) String() string {
+	switch r {
+	case SpellingRendererText:
+		return "text"
+	case SpellingRendererSSML:
+		return "ssml"
+	case SpellingRendererMorse:
+		return "morse"
+	case SpellingRendererJSON:
+		return "json"
+	default:
+		return "unknown"
+	}
+}
+
+// SpellChars returns the characters of the given string together with their names
+// in the spelling alphabet and their character class
+func (a SpellingAlphabet) SpellChars(input string) ([]SpelledChar, error) {
+	chars := make([]SpelledChar, 0, len(input))
+	for _, char := range input {
+		word, err := a.Word(char)
+		if err != nil {
+			return nil, err
+		}
+		chars = append(chars, SpelledChar{Char: string(char), Word: word, Class: charClassName(char)})
+	}
+	return chars, nil
+}
+
+// Render returns the given string spelled in the spelling alphabet in the format
+// of the given SpellingRenderer
+func (a SpellingAlphabet) Render(input string, renderer SpellingRenderer) (string, error) {
+	switch renderer {
+	case SpellingRendererText:
+		return a.Spell(input)
+	case SpellingRendererSSML:
+		chars, err := a.SpellChars(input)
+		if err != nil {
+			return "", err
+		}
+		return renderSSML(chars), nil
+	case SpellingRendererMorse:
+		return a.morse(input)
+	case SpellingRendererJSON:
+		chars, err := a.SpellChars(input)
+		if err != nil {
+			return "", err
+		}
+		output, err := json.Marshal(chars)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode spelled characters: %w", err)
+		}
+		return string(output), nil
+	default:
+		return "", fmt.Errorf("%w: %d", ErrUnknownSpellingRenderer, renderer)
+	}
+}
+
+// Morse returns the given string in international Morse code. The codes of the
+// characters are separated by a space
+func Morse(input string) (string, error) {
+	codes := make([]string, 0, len(input))
+	for _, char := range input {
+		code, ok := morseCodes[unicode.ToUpper(char)]
+		if !ok {
+			return "", fmt.Errorf("failed to convert given character to Morse code: %s is unsupported",
+				string(char))
+		}
+		codes = append(codes, code)
+	}
+	return strings.Join(codes, " "), nil
+}
+
+// morse returns the given string in international Morse code like Morse. Characters
+// without a Morse code are rendered as their name in the spelling alphabet, enclosed
+// in square brackets (i. e. "[ASTERISK]")
+func (a SpellingAlphabet) morse(input string) (string, error) {
+	codes := make([]string, 0, len(input))
+	for _, char := range input {
+		if code, ok := morseCodes[unicode.ToUpper(char)]; ok {
+			codes = append(codes, code)
+			continue
+		}
+		word, err := a.Word(char)
+		if err != nil {
+			return "", err
+		}
+		codes = append(codes, "["+word+"]")
+	}
+	return strings.Join(codes, " "), nil
+}
+
+// renderSSML returns a SSML document that reads each of the given characters as a
+// character followed by its name. Symbol names are read as lower-case words
+func renderSSML(chars []SpelledChar) string {
+	ssml := strings.Builder{}
+	ssml.WriteString("<speak>")
+	for i, char := range chars {
+		switch {
+		case i > 0 && i%SSMLGroupSize == 0:
+			ssml.WriteString(`<break time="1s"/>`)
+		case i > 0:
+			ssml.WriteString(`<break time="300ms"/>`)
+		}
+		word := char.Word
+		if char.Class == "numeric" || char.Class == "special" {
+			word = strings.ToLower(strings.ReplaceAll(word, "_", " "))
+		}
+		ssml.WriteString(`<say-as interpret-as="characters">`)
+		_ = xml.EscapeText(&ssml, []byte(char.Char))
+		ssml.WriteString(`</say-as> `)
+		_ = xml.EscapeText(&ssml, []byte(word))
+	}
+	ssml.WriteString("</speak>")
+	return ssml.String()
+}
+
+// charClassName returns the name of the character class of the given character
+func charClassName(char rune) string {
+	switch {
+	case unicode.IsUpper(char):
+		return "upper"
+	case unicode.IsLower(char):
+		return "lower"
+	case unicode.IsDigit(char):
+		return "numeric"
+	default:
+		return "special"
+	}
+}
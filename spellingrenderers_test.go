@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSpellingRendererFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    SpellingRenderer
+		wantErr bool
+	}{
+		{"text", SpellingRendererText, false},
+		{"SSML", SpellingRendererSSML, false},
+		{" morse ", SpellingRendererMorse, false},
+		{"json", SpellingRendererJSON, false},
+		{"braille", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SpellingRendererFromString(tt.name)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownSpellingRenderer) {
+					t.Errorf("SpellingRendererFromString() expected ErrUnknownSpellingRenderer, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("SpellingRendererFromString() failed: %s", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SpellingRendererFromString() failed, expected: %s, got: %s", tt.want, got)
+			}
+			if got.String() != strings.ToLower(strings.TrimSpace(tt.name)) {
+				t.Errorf("String() failed, expected: %s, got: %s", tt.name, got.String())
+			}
+		})
+	}
+}
+
+func TestMorse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"Empty", "", "", false},
+		{"SOS", "sos", "... --- ...", false},
+		{"MixedCase", "aB1?", ".- -... .---- ..--..", false},
+		{"Unsupported", "a#", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Morse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Morse() error = %v, wantErr %t", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Morse() failed, expected: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSpellingAlphabet_SpellChars(t *testing.T) {
+	chars, err := SpellingAlphabetNATO.SpellChars("aB1!")
+	if err != nil {
+		t.Fatalf("SpellChars() failed: %s", err)
+	}
+	want := []SpelledChar{
+		{Char: "a", Word: "alfa", Class: "lower"},
+		{Char: "B", Word: "Bravo", Class: "upper"},
+		{Char: "1", Word: "ONE", Class: "numeric"},
+		{Char: "!", Word: "EXCLAMATION_POINT", Class: "special"},
+	}
+	if len(chars) != len(want) {
+		t.Fatalf("SpellChars() failed, expected: %d chars, got: %d", len(want), len(chars))
+	}
+	for i := range want {
+		if chars[i] != want[i] {
+			t.Errorf("SpellChars() failed, expected: %+v, got: %+v", want[i], chars[i])
+		}
+	}
+	if _, err = SpellingAlphabetNATO.SpellChars("\x00"); err == nil {
+		t.Error("SpellChars() with unsupported character was supposed to fail")
+	}
+}
+
+func TestSpellingAlphabet_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer SpellingRenderer
+		input    string
+		want     string
+		wantErr  bool
+	}{
+		{"Text", SpellingRendererText, "aB1", "alfa/Bravo/ONE", false},
+		{"Morse", SpellingRendererMorse, "aB1", ".- -... .----", false},
+		{"MorseFallback", SpellingRendererMorse, "a#`~", ".- [CROSSHATCH] [GRAVE] [TILDE]", false},
+		{"MorseUnsupported", SpellingRendererMorse, "a\x00", "", true},
+		{
+			"SSML", SpellingRendererSSML, "a<1&B",
+			`<speak><say-as interpret-as="characters">a</say-as> alfa<break time="300ms"/>` +
+				`<say-as interpret-as="characters">&lt;</say-as> less than<break time="300ms"/>` +
+				`<say-as interpret-as="characters">1</say-as> one<break time="300ms"/>` +
+				`<say-as interpret-as="characters">&amp;</say-as> ampersand<break time="1s"/>` +
+				`<say-as interpret-as="characters">B</say-as> Bravo</speak>`,
+			false,
+		},
+		{
+			"JSON", SpellingRendererJSON, "a1",
+			`[{"char":"a","word":"alfa","class":"lower"},{"char":"1","word":"ONE","class":"numeric"}]`,
+			false,
+		},
+		{"Unsupported", SpellingRendererJSON, "\x00", "", true},
+		{"UnknownRenderer", SpellingRenderer(99), "a", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SpellingAlphabetNATO.Render(tt.input, tt.renderer)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Render() error = %v, wantErr %t", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Render() failed, expected: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSpellingAlphabet_Render_jsonRoundTrip(t *testing.T) {
+	output, err := SpellingAlphabetDIN5009.Render("Ä\"z", SpellingRendererJSON)
+	if err != nil {
+		t.Fatalf("Render() failed: %s", err)
+	}
+	var chars []SpelledChar
+	if err = json.Unmarshal([]byte(output), &chars); err != nil {
+		t.Fatalf("failed to decode JSON output: %s", err)
+	}
+	if len(chars) != 3 || chars[0].Word != "Ärger" || chars[1].Char != `"` || chars[2].Class != "lower" {
+		t.Errorf("Render() failed, unexpected JSON output: %s", output)
+	}
+}
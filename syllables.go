@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SyllableSet represents a named set of syllables that is used for the generation
+// of pronounceable passwords
+type SyllableSet struct {
+	// Name is the name of the syllable set
+	Name string
+	// Syllables holds the lower-case syllables of the set
+	Syllables []string
+}
+
+var (
+	// ErrInvalidSyllableSet is returned if a syllable set can not be used for the
+	// generation of pronounceable passwords
+	ErrInvalidSyllableSet = errors.New("invalid syllable set")
+	// ErrUnknownSyllableSet is returned if a syllable set name is not known
+	ErrUnknownSyllableSet = errors.New("unknown syllable set")
+)
+
+var (
+	// SyllableSetKoremutake is the default syllable set based on the Koremutake
+	// syllables
+	SyllableSetKoremutake = SyllableSet{Name: "koremutake", Syllables: KoremutakeSyllables}
+
+	// SyllableSetGerman is a German-flavored syllable set
+	SyllableSetGerman = SyllableSet{Name: "de", Syllables: []string{
+		"ba", "be", "bi", "bo", "bu", "da", "de", "di", "do", "du", "fa", "fe", "fi", "fo",
+		"fu", "ga", "ge", "gi", "go", "gu", "ha", "he", "hi", "ho", "hu", "ka", "ke", "ki",
+		"ko", "ku", "la", "le", "li", "lo", "lu", "ma", "me", "mi", "mo", "mu", "na", "ne",
+		"ni", "no", "nu", "pa", "pe", "pi", "po", "pu", "ra", "re", "ri", "ro", "ru", "sa",
+		"se", "si", "so", "su", "ta", "te", "ti", "to", "tu", "wa", "we", "wi", "wo", "wu",
+		"za", "ze", "zi", "zo", "zu", "bau", "bei", "fei", "hau", "kau", "lei", "mau", "rei",
+		"sei", "tau", "wei", "zei", "scha", "sche", "schi", "scho", "schu", "spa", "spe",
+		"spi", "spo", "sta", "ste", "sti", "sto", "stu", "pfa", "pfe", "pfo", "kla", "kle",
+		"kli", "klo", "bra", "bre", "bri", "bro", "gra", "gre", "gri", "gro", "tra", "tre",
+		"tri", "tro", "fra", "fre", "fri", "fro", "ber", "der", "ger", "ler", "mer", "ner",
+		"ter", "ben", "den", "gen", "len", "men", "ten", "ung", "ein", "aus", "ach", "ich",
+	}}
+
+	// SyllableSetItalian is an Italian-flavored syllable set
+	SyllableSetItalian = SyllableSet{Name: "it", Syllables: []string{
+		"ba", "be", "bi", "bo", "bu", "ca", "ce", "ci", "co", "cu", "da", "de", "di", "do",
+		"du", "fa", "fe", "fi", "fo", "fu", "ga", "ge", "gi", "go", "gu", "la", "le", "li",
+		"lo", "lu", "ma", "me", "mi", "mo", "mu", "na", "ne", "ni", "no", "nu", "pa", "pe",
+		"pi", "po", "pu", "ra", "re", "ri", "ro", "ru", "sa", "se", "si", "so", "su", "ta",
+		"te", "ti", "to", "tu", "va", "ve", "vi", "vo", "vu", "za", "ze", "zi", "zo", "zu",
+		"che", "chi", "ghe", "ghi", "gli", "gna", "gne", "gni", "gno", "sce", "sci", "cia",
+		"cio", "ciu", "gia", "gio", "giu", "tta", "tte", "tto", "lla", "lle", "llo", "zza",
+		"zzo", "nna", "nno", "ssa", "sso", "bra", "bre", "bri", "bro", "tra", "tre", "tri",
+		"tro", "pra", "pre", "pri", "pro", "sta", "ste", "sti", "sto", "spa", "spe", "fra",
+		"fre", "fri", "fro", "gra", "gre", "gri", "gro", "qua", "que", "qui", "ren", "ton",
+		"lin", "mar", "per", "con", "dal", "nel", "sol", "tan",
+	}}
+
+	// SyllableSetJapanese is a syllable set based on the Hepburn romanization of the
+	// Japanese syllabary (romaji)
+	SyllableSetJapanese = SyllableSet{Name: "ja", Syllables: []string{
+		"a", "i", "u", "e", "o", "ka", "ki", "ku", "ke", "ko", "sa", "shi", "su", "se", "so",
+		"ta", "chi", "tsu", "te", "to", "na", "ni", "nu", "ne", "no", "ha", "hi", "fu", "he",
+		"ho", "ma", "mi", "mu", "me", "mo", "ya", "yu", "yo", "ra", "ri", "ru", "re", "ro",
+		"wa", "wo", "ga", "gi", "gu", "ge", "go", "za", "ji", "zu", "ze", "zo", "da", "de",
+		"do", "ba", "bi", "bu", "be", "bo", "pa", "pi", "pu", "pe", "po", "kya", "kyu", "kyo",
+		"sha", "shu", "sho", "cha", "chu", "cho", "nya", "nyu", "nyo", "hya", "hyu", "hyo",
+		"mya", "myu", "myo", "rya", "ryu", "ryo", "gya", "gyu", "gyo", "ja", "ju", "jo",
+		"bya", "byu", "byo", "pya", "pyu", "pyo", "kan", "ken", "kin", "kon", "san", "sen",
+		"shin", "ten", "tan", "nan", "han", "man", "min", "ran", "ren", "gen", "zen", "dan",
+		"ban", "bun",
+	}}
+
+	// SyllableSetSpanish is a Spanish-flavored syllable set
+	SyllableSetSpanish = SyllableSet{Name: "es", Syllables: []string{
+		"ba", "be", "bi", "bo", "bu", "ca", "co", "cu", "que", "qui", "ce", "ci", "da", "de",
+		"di", "do", "du", "fa", "fe", "fi", "fo", "fu", "ga", "go", "gu", "gue", "gui", "ge",
+		"gi", "ja", "jo", "ju", "la", "le", "li", "lo", "lu", "ma", "me", "mi", "mo", "mu",
+		"na", "ne", "ni", "no", "nu", "pa", "pe", "pi", "po", "pu", "ra", "re", "ri", "ro",
+		"ru", "sa", "se", "si", "so", "su", "ta", "te", "ti", "to", "tu", "va", "ve", "vi",
+		"vo", "za", "zo", "zu", "cha", "che", "chi", "cho", "chu", "lla", "lle", "llo", "llu",
+		"bra", "bre", "bri", "bro", "tra", "tre", "tri", "tro", "pla", "ple", "pli", "plo",
+		"cla", "cle", "clo", "gra", "gre", "gri", "gro", "dra", "dre", "dro", "fra", "fre",
+		"fri", "fro", "ar", "er", "ir", "or", "al", "el", "en", "es", "as", "os", "ion",
+		"dad", "mos", "ado", "ido", "sol", "mar", "tan", "con", "per",
+	}}
+)
+
+// builtinSyllableSets holds the built-in syllable sets and their names
+var builtinSyllableSets = map[string]SyllableSet{
+	"koremutake": SyllableSetKoremutake,
+	"de":         SyllableSetGerman,
+	"german":     SyllableSetGerman,
+	"es":         SyllableSetSpanish,
+	"spanish":    SyllableSetSpanish,
+	"it":         SyllableSetItalian,
+	"italian":    SyllableSetItalian,
+	"ja":         SyllableSetJapanese,
+	"japanese":   SyllableSetJapanese,
+	"romaji":     SyllableSetJapanese,
+}
+
+// init validates the built-in syllable sets
+func init() {
+	for name, set := range builtinSyllableSets {
+		if err := set.Validate(); err != nil {
+			panic(fmt.Sprintf("invalid built-in syllable set %q: %s", name, err))
+		}
+	}
+}
+
+// SyllableSetByName returns the built-in syllable set with the given name. Valid
+// names are "koremutake", "de" (or "german"), "es" (or "spanish"), "it" (or
+// "italian") and "ja" (or "japanese" and "romaji")
+func SyllableSetByName(name string) (SyllableSet, error) {
+	set, ok := builtinSyllableSets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return SyllableSet{}, fmt.Errorf("%w: %s", ErrUnknownSyllableSet, name)
+	}
+	return set, nil
+}
+
+// LoadSyllableSet reads a custom syllable set from the file at the given path.
+// See ParseSyllableSet for the format of the file. The name of the set is the name
+// of the file without its extension
+func LoadSyllableSet(path string) (SyllableSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return SyllableSet{}, fmt.Errorf("failed to open syllable file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return ParseSyllableSet(name, file)
+}
+
+// ParseSyllableSet parses a syllable set with the given name from the given
+// io.Reader. The syllables are separated by whitespace or commas. Everything after a
+// "#" character up to the end of the line is ignored. The syllables are normalized
+// to NFC and converted to lower-case. The parsed set is validated
+func ParseSyllableSet(name string, reader io.Reader) (SyllableSet, error) {
+	set := SyllableSet{Name: name}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.FieldsFunc(line, func(char rune) bool {
+			return char == ',' || unicode.IsSpace(char)
+		})
+		for _, syllable := range fields {
+			set.Syllables = append(set.Syllables, strings.ToLower(norm.NFC.String(syllable)))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return SyllableSet{}, fmt.Errorf("failed to read syllable set: %w", err)
+	}
+	if err := set.Validate(); err != nil {
+		return SyllableSet{}, err
+	}
+	return set, nil
+}
+
+// Validate checks if the SyllableSet can be used for the generation of pronounceable
+// passwords. The set must not be empty and every syllable must consist of lower-case
+// letters only. Duplicate syllables are not allowed, since they bias the selection.
+// Syllables that are a concatenation of other syllables of the set (i. e. "bado" in
+// a set that contains "ba" and "do") are not allowed either, since they make the
+// syllables of a password ambiguous. Splits across syllable boundaries (i. e. "ka"
+// and "no" vs. "kan" and "o") are not checked. The entropy estimation assumes a
+// unique split of a password into syllables and therefore overstates the entropy
+// of sets that allow such splits
+func (s SyllableSet) Validate() error {
+	if len(s.Syllables) == 0 {
+		return fmt.Errorf("%w: the set is empty", ErrInvalidSyllableSet)
+	}
+	seen := make(map[string]struct{}, len(s.Syllables))
+	for _, syllable := range s.Syllables {
+		if syllable == "" {
+			return fmt.Errorf("%w: empty syllable", ErrInvalidSyllableSet)
+		}
+		for _, char := range syllable {
+			if !unicode.IsLetter(char) || !unicode.IsLower(char) {
+				return fmt.Errorf("%w: syllable %q contains characters other than lower-case letters",
+					ErrInvalidSyllableSet, syllable)
+			}
+		}
+		if _, ok := seen[syllable]; ok {
+			return fmt.Errorf("%w: duplicate syllable %q", ErrInvalidSyllableSet, syllable)
+		}
+		seen[syllable] = struct{}{}
+	}
+	for _, syllable := range s.Syllables {
+		if isSyllableConcatenation(syllable, seen) {
+			return fmt.Errorf("%w: syllable %q is a concatenation of other syllables", ErrInvalidSyllableSet,
+				syllable)
+		}
+	}
+	return nil
+}
+
+// Contains returns true if the given syllable is part of the SyllableSet. The
+// check is case-insensitive
+func (s SyllableSet) Contains(syllable string) bool {
+	syllable = strings.ToLower(syllable)
+	for _, item := range s.Syllables {
+		if item == syllable {
+			return true
+		}
+	}
+	return false
+}
+
+// isSyllableConcatenation returns true if the given syllable can be split into two
+// or more syllables of the given set of syllables
+func isSyllableConcatenation(syllable string, syllables map[string]struct{}) bool {
+	// parts[i] holds the minimum amount of syllables the first i characters of the
+	// syllable can be split into, or 0 if they can not be split. The syllable
+	// itself is not used for the split
+	chars := []rune(syllable)
+	parts := make([]int, len(chars)+1)
+	for i := 1; i <= len(chars); i++ {
+		for j := 0; j < i; j++ {
+			if (j > 0 && parts[j] == 0) || (j == 0 && i == len(chars)) {
+				continue
+			}
+			if _, ok := syllables[string(chars[j:i])]; ok && (parts[i] == 0 || parts[j]+1 < parts[i]) {
+				parts[i] = parts[j] + 1
+			}
+		}
+	}
+	return parts[len(chars)] > 1
+}
+
+// syllableSet returns the configured SyllableSet of the generator. If no syllable
+// set is configured, the Koremutake syllable set is returned
+func (g *Generator) syllableSet() SyllableSet {
+	if len(g.config.SyllableSet.Syllables) == 0 {
+		return SyllableSetKoremutake
+	}
+	return g.config.SyllableSet
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKoremutakeSyllables_unique(t *testing.T) {
+	seen := make(map[string]struct{})
+	for _, syllable := range KoremutakeSyllables {
+		if _, ok := seen[syllable]; ok {
+			t.Errorf("KoremutakeSyllables failed, syllable %q is not unique", syllable)
+		}
+		seen[syllable] = struct{}{}
+	}
+}
+
+func TestSyllableSets_builtin(t *testing.T) {
+	for name, set := range builtinSyllableSets {
+		t.Run(name, func(t *testing.T) {
+			if err := set.Validate(); err != nil {
+				t.Errorf("Validate() failed: %s", err)
+			}
+			if len(set.Syllables) < 100 {
+				t.Errorf("syllable set failed, expected at least %d syllables, got: %d", 100, len(set.Syllables))
+			}
+		})
+	}
+}
+
+func TestSyllableSetByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"koremutake", "koremutake", false},
+		{"German", "de", false},
+		{" es ", "es", false},
+		{"italian", "it", false},
+		{"romaji", "ja", false},
+		{"klingon", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := SyllableSetByName(tt.name)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownSyllableSet) {
+					t.Errorf("SyllableSetByName() expected ErrUnknownSyllableSet, got: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SyllableSetByName() failed: %s", err)
+			}
+			if set.Name != tt.want {
+				t.Errorf("SyllableSetByName() failed, expected: %s, got: %s", tt.want, set.Name)
+			}
+		})
+	}
+}
+
+func TestSyllableSet_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		syllables []string
+		wantErr   bool
+	}{
+		{"valid", []string{"ba", "do", "ka"}, false},
+		{"valid unicode", []string{"bä", "dö", "kü"}, false},
+		{"empty set", nil, true},
+		{"empty syllable", []string{"ba", ""}, true},
+		{"duplicate", []string{"ba", "do", "ba"}, true},
+		{"upper-case", []string{"ba", "Do"}, true},
+		{"digit", []string{"ba", "d0"}, true},
+		{"concatenation", []string{"ba", "do", "bado"}, true},
+		{"triple concatenation", []string{"a", "b", "c", "abc"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SyllableSet{Name: tt.name, Syllables: tt.syllables}.Validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidSyllableSet) {
+				t.Errorf("Validate() expected ErrInvalidSyllableSet, got: %s", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate() failed: %s", err)
+			}
+		})
+	}
+}
+
+func TestParseSyllableSet(t *testing.T) {
+	input := "# custom syllables\nba, Do ka  # comment\n\n  mü\n"
+	set, err := ParseSyllableSet("custom", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseSyllableSet() failed: %s", err)
+	}
+	want := []string{"ba", "do", "ka", "mü"}
+	if strings.Join(set.Syllables, ",") != strings.Join(want, ",") {
+		t.Errorf("ParseSyllableSet() failed, expected: %v, got: %v", want, set.Syllables)
+	}
+	if _, err = ParseSyllableSet("invalid", strings.NewReader("ba ba")); !errors.Is(err, ErrInvalidSyllableSet) {
+		t.Errorf("ParseSyllableSet() expected ErrInvalidSyllableSet, got: %s", err)
+	}
+}
+
+func TestLoadSyllableSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.txt")
+	if err := os.WriteFile(path, []byte("ba do ka"), 0o600); err != nil {
+		t.Fatalf("failed to write syllable file: %s", err)
+	}
+	set, err := LoadSyllableSet(path)
+	if err != nil {
+		t.Fatalf("LoadSyllableSet() failed: %s", err)
+	}
+	if set.Name != "custom" || len(set.Syllables) != 3 {
+		t.Errorf("LoadSyllableSet() failed, expected set %q with %d syllables, got: %q with %d", "custom", 3,
+			set.Name, len(set.Syllables))
+	}
+	if _, err = LoadSyllableSet(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadSyllableSet() with missing file expected to fail")
+	}
+}
+
+func TestGeneratePronounceable_syllableSet(t *testing.T) {
+	config := NewConfig(WithModeMask(ModeLowerCase), WithSyllableSet(SyllableSetJapanese))
+	generator := New(config)
+	for range 20 {
+		if _, err := generator.generatePronounceable(); err != nil {
+			t.Fatalf("generatePronounceable() failed: %s", err)
+		}
+		for _, syllable := range generator.syllables {
+			if !SyllableSetJapanese.Contains(syllable) {
+				t.Errorf("generatePronounceable() failed, syllable %q is not part of the set", syllable)
+			}
+		}
+	}
+
+	config = NewConfig(WithSyllableSet(SyllableSet{Name: "invalid", Syllables: []string{"ba", "ba"}}))
+	generator = New(config)
+	if _, err := generator.generatePronounceable(); !errors.Is(err, ErrInvalidSyllableSet) {
+		t.Errorf("generatePronounceable() expected ErrInvalidSyllableSet, got: %s", err)
+	}
+	if _, err := generator.Entropy(); !errors.Is(err, ErrInvalidSyllableSet) {
+		t.Errorf("Entropy() expected ErrInvalidSyllableSet, got: %s", err)
+	}
+}
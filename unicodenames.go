@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/unicode/runenames"
+)
+
+var (
+	// unicodeNames maps the Unicode character names as returned by unicodeCharName
+	// to their character. It is built on first use by unicodeCharByName
+	unicodeNames map[string]rune
+	// unicodeNamesOnce makes sure that unicodeNames is only built once
+	unicodeNamesOnce sync.Once
+)
+
+// unicodeCharName returns the Unicode character name of the given character with
+// underscores instead of spaces (i. e. "EURO_SIGN"). Graphic characters without an
+// individual name are named by their code point (i. e. "U+4E00"). For control and
+// other non-graphic characters an empty string is returned
+func unicodeCharName(char rune) string {
+	if name := runenames.Name(char); name != "" && !strings.HasPrefix(name, "<") {
+		return strings.ReplaceAll(name, " ", "_")
+	}
+	if unicode.IsGraphic(char) {
+		return fmt.Sprintf("U+%04X", char)
+	}
+	return ""
+}
+
+// unicodeCharByName returns the character for the given name as returned by
+// unicodeCharName
+func unicodeCharByName(name string) (rune, bool) {
+	if code, ok := strings.CutPrefix(name, "U+"); ok {
+		char, err := strconv.ParseUint(code, 16, 32)
+		if err != nil || !unicode.IsGraphic(rune(char)) {
+			return 0, false
+		}
+		return rune(char), true
+	}
+	unicodeNamesOnce.Do(func() {
+		unicodeNames = make(map[string]rune)
+		for char := rune(0); char <= unicode.MaxRune; char++ {
+			if name := runenames.Name(char); name != "" && !strings.HasPrefix(name, "<") {
+				unicodeNames[strings.ReplaceAll(name, " ", "_")] = char
+			}
+		}
+	})
+	char, ok := unicodeNames[name]
+	return char, ok
+}
+
+// markedLetterWord returns the name of a letter with diacritical marks as the name
+// of its base letter in the spelling alphabet followed by the Unicode names of the
+// marks without their "COMBINING" prefix (i. e. "echo_ACUTE_ACCENT" for "é")
+func (a SpellingAlphabet) markedLetterWord(char rune) (string, bool) {
+	decomposed := []rune(norm.NFD.String(string(char)))
+	if len(decomposed) < 2 {
+		return "", false
+	}
+	word, ok := a.Letters[unicode.ToUpper(decomposed[0])]
+	if !ok {
+		return "", false
+	}
+	if unicode.IsLower(decomposed[0]) {
+		word = strings.ToLower(word)
+	}
+	parts := []string{word}
+	for _, mark := range decomposed[1:] {
+		if !unicode.Is(unicode.Mn, mark) {
+			return "", false
+		}
+		parts = append(parts, strings.TrimPrefix(unicodeCharName(mark), "COMBINING_"))
+	}
+	return strings.Join(parts, "_"), true
+}
+
+// markedLetterChar returns the letter with diacritical marks for the given name as
+// returned by markedLetterWord
+func (a SpellingAlphabet) markedLetterChar(word string) (rune, bool) {
+	for i := range word {
+		if word[i] != '_' {
+			continue
+		}
+		base, ok := a.exactChar(word[:i])
+		if !ok || !unicode.IsLetter(base) {
+			continue
+		}
+		marks, ok := combiningMarks(word[i+1:])
+		if !ok {
+			continue
+		}
+		composed := []rune(norm.NFC.String(string(base) + marks))
+		if len(composed) == 1 {
+			return composed[0], true
+		}
+	}
+	return 0, false
+}
+
+// combiningMarks returns the combining marks for the given underscore-separated
+// list of mark names as returned by markedLetterWord
+func combiningMarks(names string) (string, bool) {
+	for i := len(names); i > 0; i-- {
+		if i < len(names) && names[i] != '_' {
+			continue
+		}
+		mark, ok := unicodeCharByName("COMBINING_" + names[:i])
+		if !ok {
+			mark, ok = unicodeCharByName(names[:i])
+		}
+		if !ok || !unicode.Is(unicode.Mn, mark) {
+			continue
+		}
+		if i == len(names) {
+			return string(mark), true
+		}
+		if rest, ok := combiningMarks(names[i+1:]); ok {
+			return string(mark) + rest, true
+		}
+	}
+	return "", false
+}
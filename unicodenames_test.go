@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"testing"
+)
+
+func TestSpellingAlphabet_Word_unicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet SpellingAlphabet
+		char     rune
+		want     string
+		wantErr  bool
+	}{
+		{"AccentedLetter", SpellingAlphabetNATO, 'é', "echo_ACUTE_ACCENT", false},
+		{"AccentedUpperCaseLetter", SpellingAlphabetNATO, 'Ç', "Charlie_CEDILLA", false},
+		{"MultipleMarks", SpellingAlphabetNATO, 'ǘ', "uniform_DIAERESIS_ACUTE_ACCENT", false},
+		{"LocalizedLetter", SpellingAlphabetDIN5009, 'ü', "übermut", false},
+		{"LocalizedBaseLetter", SpellingAlphabetDIN5009, 'é', "emil_ACUTE_ACCENT", false},
+		{"UnicodeName", SpellingAlphabetNATO, '€', "EURO_SIGN", false},
+		{"UnicodeNameSpace", SpellingAlphabetNATO, ' ', "SPACE", false},
+		{"CodePoint", SpellingAlphabetNATO, '中', "U+4E2D", false},
+		{"ControlChar", SpellingAlphabetNATO, '\t', "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.alphabet.Word(tt.char)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Word() error = %v, wantErr %t", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Word() failed, expected: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSpellingAlphabet_Unspell_unicode(t *testing.T) {
+	input := "éÇǘüÜß€ 中ñ"
+	for name, alphabet := range builtinSpellingAlphabets {
+		t.Run(name, func(t *testing.T) {
+			spelled, err := alphabet.Spell(input)
+			if err != nil {
+				t.Fatalf("Spell() failed: %s", err)
+			}
+			got, err := alphabet.Unspell(spelled)
+			if err != nil {
+				t.Fatalf("Unspell() failed: %s", err)
+			}
+			if got != input {
+				t.Errorf("Unspell() failed, expected: %s, got: %s", input, got)
+			}
+		})
+	}
+}
+
+func TestUnicodeCharByName(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   rune
+		wantOK bool
+	}{
+		{"EURO_SIGN", '€', true},
+		{"LATIN_SMALL_LETTER_SHARP_S", 'ß', true},
+		{"U+4E2D", '中', true},
+		{"U+0009", 0, false},
+		{"U+ZZZZ", 0, false},
+		{"NOT_A_CHARACTER", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := unicodeCharByName(tt.name)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("unicodeCharByName() failed, expected: %q/%t, got: %q/%t", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
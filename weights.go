@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ClassWeightOther is the key of the ClassWeights map that represents all characters
+// of the custom character classes that are not part of any character mode
+const ClassWeightOther Mode = 0
+
+// weightResolution is the total of the integer weights that are used to select the
+// character class of each character of a weighted password
+const weightResolution = 1 << 20
+
+// ErrInvalidClassWeights is returned if the configured character class weights can
+// not be applied to the character range of the password
+var ErrInvalidClassWeights = errors.New("invalid character class weights")
+
+// weightedClass represents the characters of a single character class and the
+// integer weight that is used to select the class
+type weightedClass struct {
+	chars  []string
+	weight int64
+}
+
+// ClassWeightsFromString parses a comma-separated list of character class weights
+// in the form "CLASS:WEIGHT" (i. e. "L:35,U:35,N:20,S:10"). Valid classes are L
+// (lower-case), U (upper-case), N (numeric), S (special) and X (characters of custom
+// character classes that are not part of any character mode)
+func ClassWeightsFromString(weights string) (map[Mode]float64, error) {
+	classWeights := make(map[Mode]float64)
+	for _, item := range strings.Split(weights, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		class, value, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not in the format CLASS:WEIGHT", ErrInvalidClassWeights, item)
+		}
+		var mode Mode
+		switch strings.ToUpper(strings.TrimSpace(class)) {
+		case "L":
+			mode = ModeLowerCase
+		case "N":
+			mode = ModeNumeric
+		case "S":
+			mode = ModeSpecial
+		case "U":
+			mode = ModeUpperCase
+		case "X":
+			mode = ClassWeightOther
+		default:
+			return nil, fmt.Errorf("%w: unknown character class %q", ErrInvalidClassWeights, class)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid weight %q", ErrInvalidClassWeights, value)
+		}
+		classWeights[mode] = weight
+	}
+	return classWeights, nil
+}
+
+// weightedClasses groups the given characters by their character Mode and returns
+// the groups together with the integer weight of each group. If no class weights
+// are configured, nil is returned.
+//
+// The configured weights are percentages of the password characters that are
+// expected to be of the corresponding class. Classes without a configured weight
+// share the remaining percentage in proportion to their amount of characters. If
+// all classes of the character range have a weight, the weights are relative to
+// each other and do not need to sum up to 100
+func (g *Generator) weightedClasses(chars []string) ([]weightedClass, error) {
+	if len(g.config.ClassWeights) == 0 {
+		return nil, nil
+	}
+	for mode, weight := range g.config.ClassWeights {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("%w: weight of %s characters must be a non-negative number",
+				ErrInvalidClassWeights, mode)
+		}
+	}
+
+	groups := make(map[Mode][]string)
+	for _, char := range chars {
+		mode := g.modeOfChar([]rune(char)[0])
+		groups[mode] = append(groups[mode], char)
+	}
+	var weightSum float64
+	var unweightedSize int
+	for mode, group := range groups {
+		if weight, ok := g.config.ClassWeights[mode]; ok {
+			weightSum += weight
+			continue
+		}
+		unweightedSize += len(group)
+	}
+	if (unweightedSize > 0 && weightSum > 100) || (unweightedSize == 0 && weightSum == 0) {
+		return nil, fmt.Errorf("%w: weights sum up to %g", ErrInvalidClassWeights, weightSum)
+	}
+
+	classes := make([]weightedClass, 0, len(groups))
+	for _, mode := range append([]Mode{ClassWeightOther}, charModes...) {
+		group, ok := groups[mode]
+		if !ok {
+			continue
+		}
+		var share float64
+		switch weight, ok := g.config.ClassWeights[mode]; {
+		case ok && unweightedSize == 0:
+			share = weight / weightSum
+		case ok:
+			share = weight / 100
+		default:
+			share = (1 - weightSum/100) * float64(len(group)) / float64(unweightedSize)
+		}
+		weight := int64(math.Round(share * weightResolution))
+		if share > 0 && weight == 0 {
+			weight = 1
+		}
+		if weight == 0 {
+			continue
+		}
+		classes = append(classes, weightedClass{chars: group, weight: weight})
+	}
+	return classes, nil
+}
+
+// randomStringFromClasses returns a random string of the given length based on the
+// given weighted character classes. For each character, the class is selected with
+// a probability proportional to its weight and the character is selected from the
+// class with equal probability
+func (g *Generator) randomStringFromClasses(length int64, classes []weightedClass) (string, error) {
+	if length < 1 {
+		return "", ErrInvalidLength
+	}
+	var total int64
+	for _, class := range classes {
+		total += class.weight
+	}
+	if total < 1 {
+		return "", ErrInvalidCharRange
+	}
+
+	randString := strings.Builder{}
+	for i := int64(0); i < length; i++ {
+		randNum, err := g.RandNum(total)
+		if err != nil {
+			return randString.String(), err
+		}
+		for _, class := range classes {
+			if randNum >= class.weight {
+				randNum -= class.weight
+				continue
+			}
+			index, err := g.RandNum(int64(len(class.chars)))
+			if err != nil {
+				return randString.String(), err
+			}
+			randString.WriteString(class.chars[index])
+			break
+		}
+	}
+	return randString.String(), nil
+}
+
+// charProbabilities returns the probability of each of the given characters to be
+// selected for a single position of a password. Without configured class weights
+// every character is selected with equal probability
+func (g *Generator) charProbabilities(chars []string) ([]float64, error) {
+	probabilities := make([]float64, len(chars))
+	for i := range chars {
+		probabilities[i] = 1 / float64(len(chars))
+	}
+	classes, err := g.weightedClasses(chars)
+	if err != nil || classes == nil {
+		return probabilities, err
+	}
+
+	var total int64
+	for _, class := range classes {
+		total += class.weight
+	}
+	charProbability := make(map[string]float64)
+	for _, class := range classes {
+		for _, char := range class.chars {
+			charProbability[char] = float64(class.weight) / float64(total) / float64(len(class.chars))
+		}
+	}
+	for i, char := range chars {
+		probabilities[i] = charProbability[char]
+	}
+	return probabilities, nil
+}
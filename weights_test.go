@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2021-2024 Winni Neessen <wn@neessen.dev>
+//
+// SPDX-License-Identifier: MIT
+
+package apg
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestClassWeightsFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights string
+		want    map[Mode]float64
+		wantErr bool
+	}{
+		{
+			"all classes", "L:35,U:35,N:20,S:10",
+			map[Mode]float64{ModeLowerCase: 35, ModeUpperCase: 35, ModeNumeric: 20, ModeSpecial: 10}, false,
+		},
+		{"lower-case and spaces", " s : 2.5 ,, x:1", map[Mode]float64{ModeSpecial: 2.5, ClassWeightOther: 1}, false},
+		{"empty", "", map[Mode]float64{}, false},
+		{"missing weight", "L", nil, true},
+		{"invalid weight", "L:foo", nil, true},
+		{"unknown class", "Q:10", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights, err := ClassWeightsFromString(tt.weights)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidClassWeights) {
+					t.Errorf("ClassWeightsFromString() expected ErrInvalidClassWeights, got: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ClassWeightsFromString() failed: %s", err)
+			}
+			if len(weights) != len(tt.want) {
+				t.Fatalf("ClassWeightsFromString() failed, expected: %v, got: %v", tt.want, weights)
+			}
+			for mode, weight := range tt.want {
+				if weights[mode] != weight {
+					t.Errorf("ClassWeightsFromString() failed for %s, expected: %f, got: %f", mode,
+						weight, weights[mode])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_classWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[Mode]float64
+		mode    Mode
+		want    float64
+	}{
+		{"special 10 percent", map[Mode]float64{ModeSpecial: 10}, ModeSpecial, 0.10},
+		{"numeric 50 percent", map[Mode]float64{ModeNumeric: 50}, ModeNumeric, 0.50},
+		{"relative weights", map[Mode]float64{ModeLowerCase: 3, ModeNumeric: 1, ModeSpecial: 0, ModeUpperCase: 0},
+			ModeLowerCase, 0.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeNumeric|ModeSpecial|ModeUpperCase),
+				WithFixedLength(1000))
+			config.ClassWeights = tt.weights
+			generator := New(config)
+			password, err := generator.Generate()
+			if err != nil {
+				t.Fatalf("Generate() failed: %s", err)
+			}
+			var count float64
+			for _, char := range password {
+				if strings.ContainsRune(fullCharRangeForMode(tt.mode), char) {
+					count++
+				}
+			}
+			if share := count / float64(len(password)); math.Abs(share-tt.want) > 0.06 {
+				t.Errorf("Generate() failed, expected share of %s characters: %f, got: %f", tt.mode,
+					tt.want, share)
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_classWeightsZero(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeSpecial), WithFixedLength(200),
+		WithClassWeight(ModeSpecial, 0))
+	generator := New(config)
+	password, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Generate() failed: %s", err)
+	}
+	if strings.ContainsAny(password, CharRangeSpecial) {
+		t.Errorf("Generate() failed, expected no special characters, got: %s", password)
+	}
+
+	config = NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeSpecial), WithClassWeight(ModeSpecial, 0),
+		WithMinSpecial(1))
+	generator = New(config)
+	if _, err = generator.Generate(); !errors.Is(err, ErrUnsatisfiableConstraints) {
+		t.Errorf("Generate() expected ErrUnsatisfiableConstraints, got: %s", err)
+	}
+}
+
+func TestGenerator_Generate_classWeightsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[Mode]float64
+	}{
+		{"negative weight", map[Mode]float64{ModeSpecial: -1}},
+		{"NaN weight", map[Mode]float64{ModeSpecial: math.NaN()}},
+		{"more than 100 percent", map[Mode]float64{ModeUpperCase: 60, ModeNumeric: 60}},
+		{"all zero", map[Mode]float64{ModeLowerCase: 0, ModeNumeric: 0, ModeUpperCase: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeNumeric|ModeUpperCase))
+			config.ClassWeights = tt.weights
+			generator := New(config)
+			if _, err := generator.Generate(); !errors.Is(err, ErrInvalidClassWeights) {
+				t.Errorf("Generate() expected ErrInvalidClassWeights, got: %s", err)
+			}
+			if _, err := generator.Entropy(); !errors.Is(err, ErrInvalidClassWeights) {
+				t.Errorf("Entropy() expected ErrInvalidClassWeights, got: %s", err)
+			}
+		})
+	}
+}
+
+func TestGenerator_Entropy_classWeights(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeNumeric), WithFixedLength(10),
+		WithClassWeight(ModeLowerCase, 50), WithClassWeight(ModeNumeric, 50))
+	generator := New(config)
+	got, err := generator.Entropy()
+	if err != nil {
+		t.Fatalf("Entropy() failed: %s", err)
+	}
+	// Each character is a lower-case character with a probability of 1/2 and
+	// one of 26 characters in that case (or one of 10 numeric characters)
+	want := 10 * (0.5*math.Log2(2*26) + 0.5*math.Log2(2*10))
+	if math.Abs(got-want) > 0.0001 {
+		t.Errorf("Entropy() failed, expected: %f, got: %f", want, got)
+	}
+
+	unweighted := New(NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeNumeric), WithFixedLength(10)))
+	uniform, err := unweighted.Entropy()
+	if err != nil {
+		t.Fatalf("Entropy() failed: %s", err)
+	}
+	if got >= uniform {
+		t.Errorf("Entropy() with weights expected to be lower than %f, got: %f", uniform, got)
+	}
+}
+
+func TestGenerator_probabilityUnique_classWeights(t *testing.T) {
+	config := NewConfig(WithAlgorithm(AlgoRandom), WithModeMask(ModeLowerCase|ModeNumeric), WithUniqueChars(),
+		WithCharRange(ModeLowerCase, "ab"), WithCharRange(ModeNumeric, "1"),
+		WithClassWeight(ModeLowerCase, 50), WithClassWeight(ModeNumeric, 50))
+	generator := New(config)
+	chars := []string{"a", "b", "1"}
+	probabilities, err := generator.charProbabilities(chars)
+	if err != nil {
+		t.Fatalf("charProbabilities() failed: %s", err)
+	}
+	// The probability of two unique characters is 1 minus the probability that
+	// the same character is selected twice
+	want := 1 - (0.25*0.25 + 0.25*0.25 + 0.5*0.5)
+	if got := generator.probabilityUnique(chars, probabilities, 2); math.Abs(got-want) > 0.0001 {
+		t.Errorf("probabilityUnique() failed, expected: %f, got: %f", want, got)
+	}
+}